@@ -1,16 +1,21 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/ne241099/daifugo-server/graph"
 	"github.com/ne241099/daifugo-server/infra/inmem"
 	"github.com/ne241099/daifugo-server/infra/mysql"
 	"github.com/ne241099/daifugo-server/internal/auth"
+	"github.com/ne241099/daifugo-server/internal/bot"
 	"github.com/ne241099/daifugo-server/internal/config"
 	internalMiddleware "github.com/ne241099/daifugo-server/internal/middleware"
+	"github.com/ne241099/daifugo-server/internal/presence"
 	"github.com/ne241099/daifugo-server/internal/server"
 	"github.com/ne241099/daifugo-server/internal/sse"
+	"github.com/ne241099/daifugo-server/repository"
 	"github.com/ne241099/daifugo-server/usecase/game"
 	"github.com/ne241099/daifugo-server/usecase/room"
 	"github.com/ne241099/daifugo-server/usecase/user"
@@ -27,7 +32,18 @@ func main() {
 
 	// リポジトリ初期化
 	userRepo := mysql.NewMySQLUserRepository(db)
-	roomRepo := inmem.NewInmemRoomRepository()
+	gameRecordRepo := mysql.NewMySQLGameRecordRepository(db)
+
+	// 部屋の保存先は設定で切り替える
+	var roomRepo repository.RoomRepository
+	switch cfg.RoomStore {
+	case "memory":
+		roomRepo = inmem.NewInmemRoomRepository()
+	case "mysql":
+		roomRepo = mysql.NewMySQLRoomRepository(db)
+	default:
+		panic(fmt.Sprintf("unknown ROOM_STORE: %q", cfg.RoomStore))
+	}
 
 	// 定期クリーンアップ開始
 	go func() {
@@ -48,9 +64,39 @@ func main() {
 	// SSE Hub 作成
 	hub := sse.NewHub()
 
+	// SSE・WebSocket の接続からオンライン状態を管理する
+	tracker := presence.NewTracker(cfg.PresenceGrace)
+	hub.SetPresence(tracker)
+
+	// ボットの行動を管理するドライバー
+	botDriver := &bot.Driver{
+		Strategy:       bot.NewGreedyStrategy(),
+		RoomRepository: roomRepo,
+		PlayCardUseCase: &game.PlayCardInteractor{
+			RoomRepository:       roomRepo,
+			GameRecordRepository: gameRecordRepo,
+		},
+		PassUseCase: &game.PassInteractor{
+			RoomRepository:       roomRepo,
+			GameRecordRepository: gameRecordRepo,
+		},
+		ResolveEffectUseCase: &game.ResolveEffectInteractor{
+			RoomRepository:       roomRepo,
+			GameRecordRepository: gameRecordRepo,
+		},
+		ExchangeCardsUseCase: &game.ExchangeCardsInteractor{
+			RoomRepository: roomRepo,
+		},
+		Delay: cfg.BotDelay,
+	}
+
 	// Resolver 作成
 	resolver := &graph.Resolver{
-		Hub: hub,
+		Hub:               hub,
+		BotDriver:         botDriver,
+		Presence:          tracker,
+		DisconnectTimeout: cfg.DisconnectTimeout,
+		DisconnectToBot:   cfg.DisconnectAction == "bot",
 		SignUpUseCase: &user.SignUpInteractor{
 			UserRepository: userRepo,
 		},
@@ -74,27 +120,92 @@ func main() {
 			RoomRepository: roomRepo,
 		},
 		LeaveRoomUseCase: &room.LeaveRoomInteractor{
-			RoomRepository: roomRepo,
+			RoomRepository:       roomRepo,
+			GameRecordRepository: gameRecordRepo,
 		},
 		ListRoomsUseCase: &room.ListRoomsInteractor{
 			RoomRepository: roomRepo,
 		},
+		ListUserRoomsUseCase: &room.ListUserRoomsInteractor{
+			RoomRepository: roomRepo,
+		},
+		ListDueRoomsUseCase: &room.ListDueRoomsInteractor{
+			RoomRepository: roomRepo,
+		},
 		GetRoomUseCase: &room.GetRoomInteractor{
 			RoomRepository: roomRepo,
 		},
-		StartGameUseCase: &game.StartGameInteractor{
+		AddBotUseCase: &room.AddBotInteractor{
 			RoomRepository: roomRepo,
 		},
-		RestartGameUseCase: &game.RestartGameInteractor{
+		RemoveBotUseCase: &room.RemoveBotInteractor{
 			RoomRepository: roomRepo,
 		},
-		PlayCardUseCase: &game.PlayCardInteractor{
+		SpectateRoomUseCase: &room.SpectateRoomInteractor{
+			RoomRepository: roomRepo,
+		},
+		StopSpectatingUseCase: &room.StopSpectatingInteractor{
 			RoomRepository: roomRepo,
 		},
+		UpdateSpectatorViewUseCase: &room.UpdateSpectatorViewInteractor{
+			RoomRepository: roomRepo,
+		},
+		StartGameUseCase: &game.StartGameInteractor{
+			RoomRepository:  roomRepo,
+			ExchangeTimeout: cfg.ExchangeTimeout,
+		},
+		RestartGameUseCase: &game.RestartGameInteractor{
+			RoomRepository:       roomRepo,
+			GameRecordRepository: gameRecordRepo,
+		},
+		PlayCardUseCase: &game.PlayCardInteractor{
+			RoomRepository:       roomRepo,
+			GameRecordRepository: gameRecordRepo,
+		},
 		PassUseCase: &game.PassInteractor{
+			RoomRepository:       roomRepo,
+			GameRecordRepository: gameRecordRepo,
+		},
+		ResolveEffectUseCase: &game.ResolveEffectInteractor{
+			RoomRepository:       roomRepo,
+			GameRecordRepository: gameRecordRepo,
+		},
+		ExchangeCardsUseCase: &game.ExchangeCardsInteractor{
+			RoomRepository: roomRepo,
+		},
+		AutoExchangeUseCase: &game.AutoExchangeInteractor{
+			RoomRepository: roomRepo,
+		},
+		GetReplayUseCase: &game.GetReplayInteractor{
+			GameRecordRepository: gameRecordRepo,
+		},
+		GetGameRecordUseCase: &game.GetGameRecordInteractor{
+			GameRecordRepository: gameRecordRepo,
+		},
+		VerifyDealUseCase: &game.VerifyDealInteractor{
+			GameRecordRepository: gameRecordRepo,
+		},
+		TimeoutTurnUseCase: &game.TimeoutTurnInteractor{
+			RoomRepository:       roomRepo,
+			GameRecordRepository: gameRecordRepo,
+		},
+		ForceActionUseCase: &game.ForceActionInteractor{
+			RoomRepository:       roomRepo,
+			GameRecordRepository: gameRecordRepo,
+		},
+		SetAutoPlayUseCase: &game.SetAutoPlayInteractor{
 			RoomRepository: roomRepo,
 		},
 	}
+
+	// ボットの行動も、人間の操作と同じように配信する
+	botDriver.Notify = resolver.PublishGameUpdate
+	// オンライン状態の変化を配信し、再接続したプレイヤーに席を戻す
+	tracker.OnChange(resolver.HandlePresenceChange)
+
+	// 手番の制限時間の監視開始
+	go resolver.WatchTurnDeadlines(context.Background(), 1*time.Second)
+
 	// サーバー作成
 	srv := server.New(resolver, hub, authMiddleware)
 
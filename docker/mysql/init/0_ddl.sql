@@ -7,4 +7,57 @@ CREATE TABLE IF NOT EXISTS users (
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 );
 
-ALTER TABLE users ADD COLUMN token_version INT NOT NULL DEFAULT 1;
\ No newline at end of file
+ALTER TABLE users ADD COLUMN token_version INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS game_records (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    room_id BIGINT NOT NULL,
+    record JSON NOT NULL,
+    finished_at DATETIME NOT NULL,
+    INDEX idx_game_records_room_id (room_id)
+);
+
+-- 記録したゲームの SeedHash（同じゲームを二重に記録しないために使う）
+ALTER TABLE game_records
+    ADD COLUMN seed_hash CHAR(64) NULL AFTER room_id,
+    ADD UNIQUE INDEX uq_game_records_room_seed (room_id, seed_hash);
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner_id BIGINT NOT NULL,
+    bot_ids JSON NOT NULL,
+    prev_ranks JSON NOT NULL,
+    rules JSON NOT NULL,
+    spectator_full_view BOOLEAN NOT NULL DEFAULT FALSE,
+    spectator_delay INT NOT NULL,
+    -- ゲームの状態（JSON）と、その形式のバージョン
+    game_state JSON NULL,
+    game_state_version INT NOT NULL,
+    created_at DATETIME(6) NOT NULL,
+    updated_at DATETIME(6) NOT NULL,
+    INDEX idx_rooms_updated_at (updated_at)
+);
+
+-- 保存するたびに増やす版数（同時に更新された場合の競合の検出に使う）
+ALTER TABLE rooms ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- ゲームが進行中か、次に自動で進める期限（期限切れと切断の監視で、進行中の部屋だけを読むために使う）
+ALTER TABLE rooms
+    ADD COLUMN playing BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN next_deadline DATETIME(6) NULL,
+    ADD INDEX idx_rooms_playing (playing),
+    ADD INDEX idx_rooms_next_deadline (next_deadline);
+
+CREATE TABLE IF NOT EXISTS room_members (
+    room_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    role ENUM('member', 'spectator') NOT NULL,
+    -- 参加した順（席順）
+    position INT NOT NULL,
+    PRIMARY KEY (room_id, user_id),
+    FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+-- ユーザーが参加・観戦している部屋を探すための索引
+ALTER TABLE room_members ADD INDEX idx_room_members_user_id (user_id);
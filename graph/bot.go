@@ -0,0 +1,8 @@
+package graph
+
+// triggerBots はボットの手番があれば行動させる
+func (r *Resolver) triggerBots(roomID int64) {
+	if r.BotDriver != nil {
+		r.BotDriver.Trigger(roomID)
+	}
+}
@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ne241099/daifugo-server/graph/model"
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/internal/sse"
+)
+
+// GameUpdateVersion は game_update イベントの形式のバージョン
+// 互換性のない変更をしたら上げる
+const GameUpdateVersion = 1
+
+// GameUpdate は game_update イベントで送るゲームの差分
+// クライアントは手元の seq が PrevSeq と一致すればそのまま適用でき、
+// 一致しなければ取りこぼしがあるので room を取得し直す
+type GameUpdate struct {
+	Version int    `json:"version"`
+	RoomID  string `json:"roomID"`
+	// 最初に起きた出来事の種類（cards_played, passed など）
+	Kind string `json:"kind"`
+	// この更新を適用した後のゲームの通し番号
+	Seq int64 `json:"seq"`
+	// この更新の直前の通し番号
+	PrevSeq int64              `json:"prevSeq"`
+	Events  []*model.GameEvent `json:"events"`
+	Delta   *GameDelta         `json:"delta"`
+}
+
+// GameDelta は更新後の公開情報（手札の中身は含まない）
+type GameDelta struct {
+	Phase               model.GamePhase `json:"phase"`
+	CurrentPlayerID     *string         `json:"currentPlayerID"`
+	LastPlayerID        *string         `json:"lastPlayerID"`
+	FieldCards          []*game.Card    `json:"fieldCards"`
+	TableCleared        bool            `json:"tableCleared"`
+	IsRevolution        bool            `json:"isRevolution"`
+	IsElevenBack        bool            `json:"isElevenBack"`
+	EffectiveRevolution bool            `json:"effectiveRevolution"`
+	HandCounts          map[string]int  `json:"handCounts"`
+	FinishedPlayerIDs   []string        `json:"finishedPlayerIDs"`
+	PendingEffect       map[string]any  `json:"pendingEffect,omitempty"`
+	TurnDeadline        *time.Time      `json:"turnDeadline,omitempty"`
+	IsFinished          bool            `json:"isFinished"`
+}
+
+// buildGameUpdate は前回配信した後に起きた出来事と、現在の公開情報をまとめる
+// 新しい出来事がなければ nil を返す
+func buildGameUpdate(roomID int64, g *game.Game, lastSeq int64) *GameUpdate {
+	// 新しく作り直されたゲームは通し番号が振り直されている
+	if lastSeq > g.LastEventSeq {
+		lastSeq = 0
+	}
+	events := g.EventsSince(lastSeq)
+	if len(events) == 0 {
+		return nil
+	}
+
+	delta := &GameDelta{
+		Phase:               mapGamePhase(g.Phase()),
+		CurrentPlayerID:     optionalID(g.CurrentPlayerID()),
+		LastPlayerID:        optionalID(g.LastPlayerID),
+		FieldCards:          append([]*game.Card{}, g.FieldCards...),
+		IsRevolution:        g.IsRevolution,
+		IsElevenBack:        g.IsElevenBack(),
+		EffectiveRevolution: g.EffectiveRevolution(),
+		HandCounts:          make(map[string]int, len(g.Players)),
+		FinishedPlayerIDs:   make([]string, len(g.FinishedPlayers)),
+		IsFinished:          g.IsFinished,
+	}
+	for _, ev := range events {
+		if ev.Type == game.EventTableCleared {
+			delta.TableCleared = true
+		}
+	}
+	for _, p := range g.Players {
+		delta.HandCounts[strconv.FormatInt(p.UserID, 10)] = len(p.Hand)
+	}
+	for i, p := range g.FinishedPlayers {
+		delta.FinishedPlayerIDs[i] = strconv.FormatInt(p.UserID, 10)
+	}
+	if effect := g.PendingEffect; effect != nil {
+		delta.PendingEffect = map[string]any{
+			"type":   effect.Type.String(),
+			"userID": strconv.FormatInt(effect.UserID, 10),
+			"count":  effect.Count,
+		}
+	}
+	if !g.TurnDeadline.IsZero() {
+		deadline := g.TurnDeadline
+		delta.TurnDeadline = &deadline
+	}
+
+	return &GameUpdate{
+		Version: GameUpdateVersion,
+		RoomID:  strconv.FormatInt(roomID, 10),
+		Kind:    string(events[0].Type),
+		Seq:     g.LastEventSeq,
+		PrevSeq: events[0].Seq - 1,
+		Events:  mapGameEvents(events),
+		Delta:   delta,
+	}
+}
+
+// publishGameDelta は前回の配信以降の差分を部屋へ配信する
+// 同時に呼ばれても通し番号の順に届くよう、差分の作成と配信をまとめて排他する
+func (r *Resolver) publishGameDelta(roomID int64) {
+	if r.Hub == nil {
+		return
+	}
+
+	r.publishMu.Lock()
+	defer r.publishMu.Unlock()
+
+	// 古い状態で差分を作らないよう、排他した中で最新の部屋を取得する
+	room, err := r.GetRoomUseCase.Execute(context.Background(), roomID)
+	if err != nil {
+		return
+	}
+
+	g := room.Game
+	if g == nil {
+		return
+	}
+	if r.publishedSeqs == nil {
+		r.publishedSeqs = make(map[int64]int64)
+		r.delayedSeqs = make(map[int64]int64)
+	}
+
+	if g.SpectatorDelay == 0 {
+		if update := r.publishUpdate(roomID, g, r.publishedSeqs, nil); update != nil {
+			r.delayedSeqs[roomID] = update.Seq
+		}
+		return
+	}
+
+	// 観戦者に遅れて全員の手札を見せるゲームでは、今の出来事はメンバーだけに送り、
+	// 観戦者には遅延表示の時点までの出来事を送る（合わせても今の手札が分からないように）
+	r.publishUpdate(roomID, g, r.publishedSeqs, room.IsMember)
+
+	view := g
+	if !g.IsFinished {
+		if view, err = g.DelayedView(g.SpectatorDelay); err != nil {
+			return
+		}
+	}
+	r.publishUpdate(roomID, view, r.delayedSeqs, func(userID int64) bool {
+		return !room.IsMember(userID)
+	})
+}
+
+// publishUpdate は seqs に記録した通し番号より後の差分を、部屋のうち to のユーザーへ配信する
+func (r *Resolver) publishUpdate(roomID int64, g *game.Game, seqs map[int64]int64, to func(userID int64) bool) *GameUpdate {
+	update := buildGameUpdate(roomID, g, seqs[roomID])
+	if update == nil {
+		return nil
+	}
+	seqs[roomID] = update.Seq
+	r.Hub.PublishTo(sse.RoomTopic(roomID), "game_update", update, nil, to)
+	return update
+}
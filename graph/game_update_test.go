@@ -0,0 +1,137 @@
+package graph
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/ne241099/daifugo-server/infra/inmem"
+	"github.com/ne241099/daifugo-server/internal/auth"
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/internal/sse"
+	domain "github.com/ne241099/daifugo-server/model"
+	usecase "github.com/ne241099/daifugo-server/usecase/room"
+)
+
+// receivedEvents は届いている game_update の出来事を読み出す
+func receivedEvents(c *sse.Client) []*game.Event {
+	var out []*game.Event
+	for {
+		select {
+		case ev := <-c.Events():
+			update, ok := ev.Data.(*GameUpdate)
+			if !ok {
+				continue
+			}
+			for _, e := range update.Events {
+				out = append(out, &game.Event{Seq: int64(e.Seq), Type: game.EventType(e.Type), Cards: e.Cards})
+			}
+		default:
+			return out
+		}
+	}
+}
+
+// rebuildHands は観戦者の遅延表示の手札から、その後に届いた出来事で出されたカードを除く
+func rebuildHands(view *game.Game, events []*game.Event) map[int]bool {
+	cards := make(map[int]bool)
+	for _, p := range view.Players {
+		for _, c := range p.Hand {
+			cards[c.ID] = true
+		}
+	}
+	for _, ev := range events {
+		if ev.Seq <= view.LastEventSeq {
+			continue
+		}
+		for _, c := range ev.Cards {
+			delete(cards, c.ID)
+		}
+	}
+	return cards
+}
+
+func TestFullViewSpectatorCannotRebuildLiveHands(t *testing.T) {
+	ctx := context.Background()
+	rooms := inmem.NewInmemRoomRepository()
+	hub := sse.NewHub()
+	r := &Resolver{Hub: hub, GetRoomUseCase: &usecase.GetRoomInteractor{RoomRepository: rooms}}
+
+	const spectatorID = 3
+	room := domain.NewRoom("test", 1, game.RuleSet{})
+	room.MemberIDs = []int64{1, 2}
+	room.SpectatorIDs = []int64{spectatorID}
+	room.SpectatorFullView = true
+	room.SpectatorDelay = 2
+	room.StartGame()
+	if err := rooms.SaveRoom(ctx, room); err != nil {
+		t.Fatal(err)
+	}
+
+	member := hub.Subscribe(1, sse.RoomTopic(room.ID))
+	spectator := hub.Subscribe(spectatorID, sse.RoomTopic(room.ID))
+	r.PublishGameUpdate(room.ID)
+
+	var memberEvents, spectatorEvents []*game.Event
+	for step := 0; ; step++ {
+		if step > 100 {
+			t.Fatal("続けて出す局面にならない")
+		}
+		stored, err := rooms.GetRoomByID(ctx, room.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 途中で設定を切り替えても、進行中のゲームには反映しない
+		stored.SpectatorFullView = false
+
+		g := stored.Game
+		p := g.Players[g.Turn]
+		played := false
+		if moves := g.LegalMoves(p.UserID); len(moves) > 0 {
+			err, played = g.Play(p.UserID, moves[0]), true
+		} else {
+			err = g.Pass(p.UserID)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rooms.SaveRoom(ctx, stored); err != nil {
+			t.Fatal(err)
+		}
+		r.PublishGameUpdate(room.ID)
+		memberEvents = append(memberEvents, receivedEvents(member)...)
+		spectatorEvents = append(spectatorEvents, receivedEvents(spectator)...)
+
+		// 直前の2手とも出していれば、遅延表示の後にカードが動いている
+		actions := g.Record.Actions
+		if played && step >= 4 && actions[len(actions)-2].Type == game.ActionPlay {
+			break
+		}
+	}
+
+	live, err := rooms.GetRoomByID(ctx, room.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gqlRoom := mapRoomToGraphQL(live)
+	view, err := (&roomResolver{r}).Game(auth.WithUserID(ctx, spectatorID), gqlRoom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !view.RevealHands {
+		t.Fatal("観戦者に全員の手札が見えていない")
+	}
+
+	if last := memberEvents[len(memberEvents)-1].Seq; last != live.Game.LastEventSeq {
+		t.Fatalf("メンバーに届いた通し番号 = %d, want %d", last, live.Game.LastEventSeq)
+	}
+	for _, ev := range spectatorEvents {
+		if ev.Seq > view.LastEventSeq {
+			t.Fatalf("遅延表示より後の出来事 %d (%s) が観戦者に届いた", ev.Seq, ev.Type)
+		}
+	}
+
+	if maps.Equal(rebuildHands(view, spectatorEvents), rebuildHands(live.Game, nil)) {
+		t.Fatal("観戦者が今の手札を再現できてしまう")
+	}
+}
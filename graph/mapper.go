@@ -4,21 +4,33 @@ import (
 	"strconv"
 
 	"github.com/ne241099/daifugo-server/graph/model"
+	"github.com/ne241099/daifugo-server/internal/game"
 	domain "github.com/ne241099/daifugo-server/model"
 )
 
 func mapRoomToGraphQL(r *domain.Room) *model.Room {
 	gRoom := &model.Room{
-		ID:        strconv.FormatInt(r.ID, 10),
-		Name:      r.Name,
-		OwnerID:   strconv.FormatInt(r.OwnerID, 10),
-		MemberIDs: make([]string, len(r.MemberIDs)),
-		CreatedAt: r.CreatedAt,
-		UpdatedAt: r.UpdatedAt,
+		ID:                strconv.FormatInt(r.ID, 10),
+		Name:              r.Name,
+		OwnerID:           strconv.FormatInt(r.OwnerID, 10),
+		MemberIDs:         make([]string, len(r.MemberIDs)),
+		BotIDs:            make([]string, len(r.BotIDs)),
+		SpectatorIDs:      make([]string, len(r.SpectatorIDs)),
+		SpectatorFullView: r.SpectatorFullView,
+		SpectatorDelay:    int32(r.SpectatorDelay),
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
+		Rules:             &r.Rules,
 	}
 	for i, mid := range r.MemberIDs {
 		gRoom.MemberIDs[i] = strconv.FormatInt(mid, 10)
 	}
+	for i, bid := range r.BotIDs {
+		gRoom.BotIDs[i] = strconv.FormatInt(bid, 10)
+	}
+	for i, sid := range r.SpectatorIDs {
+		gRoom.SpectatorIDs[i] = strconv.FormatInt(sid, 10)
+	}
 
 	if r.Game != nil {
 		gRoom.Game = r.Game
@@ -26,3 +38,135 @@ func mapRoomToGraphQL(r *domain.Room) *model.Room {
 
 	return gRoom
 }
+
+// mapGameEvents はゲームのイベントを GraphQL の型に変換する
+func mapGameEvents(events []*game.Event) []*model.GameEvent {
+	result := make([]*model.GameEvent, len(events))
+	for i, ev := range events {
+		gEvent := &model.GameEvent{
+			Seq:          int32(ev.Seq),
+			Type:         string(ev.Type),
+			Cards:        ev.Cards,
+			IsRevolution: ev.IsRevolution,
+			Ranking:      make([]string, len(ev.Ranking)),
+		}
+		if gEvent.Cards == nil {
+			gEvent.Cards = []*game.Card{}
+		}
+		if ev.UserID != 0 {
+			uid := strconv.FormatInt(ev.UserID, 10)
+			gEvent.UserID = &uid
+		}
+		if ev.Rank > 0 {
+			rank := int32(ev.Rank)
+			gEvent.Rank = &rank
+		}
+		if ev.Reason != "" {
+			reason := ev.Reason
+			gEvent.Reason = &reason
+		}
+		for j, uid := range ev.Ranking {
+			gEvent.Ranking[j] = strconv.FormatInt(uid, 10)
+		}
+		result[i] = gEvent
+	}
+	return result
+}
+
+// mapGamePlayers はゲームの席を、見ている人に合わせて表示する GamePlayer に変換する
+func mapGamePlayers(g *game.Game, players []*game.Player, viewerID int64) []*model.GamePlayer {
+	result := make([]*model.GamePlayer, 0, len(players))
+	for _, p := range players {
+		// nilチェック (パニック防止)
+		if p == nil {
+			continue
+		}
+		result = append(result, &model.GamePlayer{Player: p, Game: g, ViewerID: viewerID})
+	}
+	return result
+}
+
+// optionalID はIDを文字列に変換する（0なら nil）
+func optionalID(id int64) *string {
+	if id == 0 {
+		return nil
+	}
+	s := strconv.FormatInt(id, 10)
+	return &s
+}
+
+// mapGamePhase はゲームの進行段階を GraphQL の列挙型に変換する
+func mapGamePhase(p game.Phase) model.GamePhase {
+	switch p {
+	case game.PhaseDealing:
+		return model.GamePhaseDealing
+	case game.PhaseExchange:
+		return model.GamePhaseExchange
+	case game.PhaseFinished:
+		return model.GamePhaseFinished
+	default:
+		return model.GamePhasePlaying
+	}
+}
+
+// mapHandType は役の種類を GraphQL の列挙型に変換する
+func mapHandType(t game.HandType) model.HandType {
+	switch t {
+	case game.HandTypeSingle:
+		return model.HandTypeSingle
+	case game.HandTypePair:
+		return model.HandTypePair
+	case game.HandTypeSequence:
+		return model.HandTypeSequence
+	default:
+		return model.HandTypeNone
+	}
+}
+
+// mapRuleSetInput は入力をルール設定に変換する（未指定の項目はデフォルト値）
+func mapRuleSetInput(in *model.RuleSetInput) game.RuleSet {
+	rules := game.DefaultRuleSet()
+	if in == nil {
+		return rules
+	}
+	if in.EightCut != nil {
+		rules.EightCut = *in.EightCut
+	}
+	if in.ElevenBack != nil {
+		rules.ElevenBack = *in.ElevenBack
+	}
+	if in.SpadeThree != nil {
+		rules.SpadeThree = *in.SpadeThree
+	}
+	if in.Revolution != nil {
+		rules.Revolution = *in.Revolution
+	}
+	if in.MiyakoOchi != nil {
+		rules.MiyakoOchi = *in.MiyakoOchi
+	}
+	if in.Shibari != nil {
+		rules.Shibari = *in.Shibari
+	}
+	if in.SevenPass != nil {
+		rules.SevenPass = *in.SevenPass
+	}
+	if in.TenDiscard != nil {
+		rules.TenDiscard = *in.TenDiscard
+	}
+	if in.NineReverse != nil {
+		rules.NineReverse = *in.NineReverse
+	}
+	if in.FiveSkip != nil {
+		rules.FiveSkip = *in.FiveSkip
+	}
+	if in.ForbiddenFinish != nil {
+		rules.ForbiddenFinish = *in.ForbiddenFinish
+	}
+	if in.JokerCount != nil {
+		rules.JokerCount = int(*in.JokerCount)
+	}
+	if in.TurnTimeLimit != nil {
+		rules.TurnTimeLimit = int(*in.TurnTimeLimit)
+	}
+	return rules
+}
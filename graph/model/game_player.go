@@ -0,0 +1,13 @@
+package model
+
+import "github.com/ne241099/daifugo-server/internal/game"
+
+// GamePlayer はゲームの席を、見ている人に合わせて表示するためのもの
+// 手札を隠すか、出せる手を返すかは Game と ViewerID からフィールドごとに決める
+type GamePlayer struct {
+	*game.Player
+	// 席があるゲーム（観戦者向けの遅延表示ならその時点のもの）
+	Game *game.Game
+	// 見ている人のユーザーID
+	ViewerID int64
+}
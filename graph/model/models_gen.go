@@ -3,6 +3,10 @@
 package model
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
 	"time"
 
 	"github.com/ne241099/daifugo-server/internal/game"
@@ -13,30 +17,91 @@ type AuthPayload struct {
 	User  *User  `json:"user"`
 }
 
+type DealVerification struct {
+	RecordID string         `json:"recordID"`
+	Seed     string         `json:"seed"`
+	SeedHash string         `json:"seedHash"`
+	Valid    bool           `json:"valid"`
+	Reason   *string        `json:"reason,omitempty"`
+	Hands    [][]*game.Card `json:"hands"`
+}
+
+type GameEvent struct {
+	Seq          int32        `json:"seq"`
+	Type         string       `json:"type"`
+	UserID       *string      `json:"userID,omitempty"`
+	Cards        []*game.Card `json:"cards"`
+	IsRevolution bool         `json:"isRevolution"`
+	Rank         *int32       `json:"rank,omitempty"`
+	Reason       *string      `json:"reason,omitempty"`
+	Ranking      []string     `json:"ranking"`
+}
+
+type LobbyEvent struct {
+	Type   string `json:"type"`
+	RoomID string `json:"roomID"`
+	Room   *Room  `json:"room,omitempty"`
+}
+
 type Mutation struct {
 }
 
 type Query struct {
 }
 
+type Replay struct {
+	ID         string     `json:"id"`
+	RoomID     string     `json:"roomID"`
+	Step       int32      `json:"step"`
+	TotalSteps int32      `json:"totalSteps"`
+	FinishedAt time.Time  `json:"finishedAt"`
+	Game       *game.Game `json:"game"`
+}
+
 type Room struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	OwnerID   string     `json:"ownerID"`
-	MemberIDs []string   `json:"memberIDs"`
-	Owner     *User      `json:"owner"`
-	Members   []*User    `json:"members"`
-	Game      *game.Game `json:"game,omitempty"`
-	CreatedAt time.Time  `json:"createdAt"`
-	UpdatedAt time.Time  `json:"updatedAt"`
+	ID                string        `json:"id"`
+	Name              string        `json:"name"`
+	OwnerID           string        `json:"ownerID"`
+	MemberIDs         []string      `json:"memberIDs"`
+	BotIDs            []string      `json:"botIDs"`
+	SpectatorIDs      []string      `json:"spectatorIDs"`
+	SpectatorFullView bool          `json:"spectatorFullView"`
+	SpectatorDelay    int32         `json:"spectatorDelay"`
+	Owner             *User         `json:"owner"`
+	Members           []*User       `json:"members"`
+	Game              *game.Game    `json:"game,omitempty"`
+	Rules             *game.RuleSet `json:"rules"`
+	CreatedAt         time.Time     `json:"createdAt"`
+	UpdatedAt         time.Time     `json:"updatedAt"`
+}
+
+type RuleSetInput struct {
+	EightCut        *bool  `json:"eightCut,omitempty"`
+	ElevenBack      *bool  `json:"elevenBack,omitempty"`
+	SpadeThree      *bool  `json:"spadeThree,omitempty"`
+	Revolution      *bool  `json:"revolution,omitempty"`
+	MiyakoOchi      *bool  `json:"miyakoOchi,omitempty"`
+	Shibari         *bool  `json:"shibari,omitempty"`
+	SevenPass       *bool  `json:"sevenPass,omitempty"`
+	TenDiscard      *bool  `json:"tenDiscard,omitempty"`
+	NineReverse     *bool  `json:"nineReverse,omitempty"`
+	FiveSkip        *bool  `json:"fiveSkip,omitempty"`
+	ForbiddenFinish *bool  `json:"forbiddenFinish,omitempty"`
+	JokerCount      *int32 `json:"jokerCount,omitempty"`
+	TurnTimeLimit   *int32 `json:"turnTimeLimit,omitempty"`
+}
+
+type Subscription struct {
 }
 
 type User struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	IsOnline   bool       `json:"isOnline"`
+	LastSeenAt *time.Time `json:"lastSeenAt,omitempty"`
 }
 
 type SignUpInput struct {
@@ -44,3 +109,121 @@ type SignUpInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+type GamePhase string
+
+const (
+	GamePhaseDealing  GamePhase = "DEALING"
+	GamePhaseExchange GamePhase = "EXCHANGE"
+	GamePhasePlaying  GamePhase = "PLAYING"
+	GamePhaseFinished GamePhase = "FINISHED"
+)
+
+var AllGamePhase = []GamePhase{
+	GamePhaseDealing,
+	GamePhaseExchange,
+	GamePhasePlaying,
+	GamePhaseFinished,
+}
+
+func (e GamePhase) IsValid() bool {
+	switch e {
+	case GamePhaseDealing, GamePhaseExchange, GamePhasePlaying, GamePhaseFinished:
+		return true
+	}
+	return false
+}
+
+func (e GamePhase) String() string {
+	return string(e)
+}
+
+func (e *GamePhase) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GamePhase(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GamePhase", str)
+	}
+	return nil
+}
+
+func (e GamePhase) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GamePhase) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GamePhase) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type HandType string
+
+const (
+	HandTypeNone     HandType = "NONE"
+	HandTypeSingle   HandType = "SINGLE"
+	HandTypePair     HandType = "PAIR"
+	HandTypeSequence HandType = "SEQUENCE"
+)
+
+var AllHandType = []HandType{
+	HandTypeNone,
+	HandTypeSingle,
+	HandTypePair,
+	HandTypeSequence,
+}
+
+func (e HandType) IsValid() bool {
+	switch e {
+	case HandTypeNone, HandTypeSingle, HandTypePair, HandTypeSequence:
+		return true
+	}
+	return false
+}
+
+func (e HandType) String() string {
+	return string(e)
+}
+
+func (e *HandType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = HandType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid HandType", str)
+	}
+	return nil
+}
+
+func (e HandType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *HandType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e HandType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
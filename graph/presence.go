@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	domain "github.com/ne241099/daifugo-server/model"
+)
+
+// presenceOf はユーザーがオンラインかと、最後に接続していた時刻を返す（不明なら nil）
+func (r *Resolver) presenceOf(userID int64) (bool, *time.Time) {
+	if r.Presence == nil {
+		return false, nil
+	}
+	online, lastSeen := r.Presence.Status(userID)
+	if lastSeen.IsZero() {
+		return online, nil
+	}
+	return online, &lastSeen
+}
+
+// HandlePresenceChange はオンライン状態の変化を参加・観戦中の部屋へ配信する
+// 再接続したプレイヤーの席は、ボットの代打ちから本人に戻す
+func (r *Resolver) HandlePresenceChange(userID int64, online bool) {
+	ctx := context.Background()
+	rooms, err := r.ListUserRoomsUseCase.Execute(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	_, lastSeen := r.presenceOf(userID)
+	data := map[string]any{
+		"userID":     strconv.FormatInt(userID, 10),
+		"online":     online,
+		"lastSeenAt": lastSeen,
+	}
+
+	for _, room := range rooms {
+		if !room.IsMember(userID) && !room.IsSpectator(userID) {
+			continue
+		}
+
+		r.publishRoom(room.ID, "presence", data)
+
+		if online && r.SetAutoPlayUseCase != nil {
+			if _, changed, err := r.SetAutoPlayUseCase.Execute(ctx, room.ID, userID, false); err == nil && changed {
+				r.publishAutoPlay(room.ID, userID, false)
+			}
+		}
+	}
+}
+
+// disconnectedUsers は切断したまま DisconnectTimeout を過ぎたユーザーのIDを返す（監視しない設定なら nil）
+func (r *Resolver) disconnectedUsers(now time.Time) []int64 {
+	if r.Presence == nil || r.DisconnectTimeout <= 0 {
+		return nil
+	}
+	return r.Presence.OfflineBefore(now.Add(-r.DisconnectTimeout))
+}
+
+// handleDisconnected は切断したまま DisconnectTimeout を過ぎたプレイヤーの入力待ちを進める
+// 設定に応じて、パス（交換・効果は弱いカードを自動で選ぶ）するか、ボットに代打ちさせる
+func (r *Resolver) handleDisconnected(ctx context.Context, room *domain.Room, now time.Time) {
+	if r.Presence == nil || r.DisconnectTimeout <= 0 {
+		return
+	}
+
+	var waiting []int64
+	if g := room.Game; g != nil {
+		for _, uid := range g.WaitingUserIDs() {
+			if !g.IsBotControlled(uid) {
+				waiting = append(waiting, uid)
+			}
+		}
+	}
+
+	for _, uid := range waiting {
+		since, offline := r.Presence.OfflineSince(uid)
+		if !offline || now.Sub(since) < r.DisconnectTimeout {
+			continue
+		}
+		r.actForDisconnected(ctx, room.ID, uid)
+	}
+}
+
+func (r *Resolver) actForDisconnected(ctx context.Context, roomID, userID int64) {
+	if r.DisconnectToBot {
+		_, changed, err := r.SetAutoPlayUseCase.Execute(ctx, roomID, userID, true)
+		if err != nil || !changed {
+			return
+		}
+		r.publishAutoPlay(roomID, userID, true)
+		r.triggerBots(roomID)
+		return
+	}
+
+	_, acted, err := r.ForceActionUseCase.Execute(ctx, roomID, userID)
+	if err != nil || !acted {
+		return
+	}
+	r.PublishGameUpdate(roomID)
+	r.triggerBots(roomID)
+}
+
+// publishAutoPlay はボットの代打ちの切り替えを部屋へ配信する
+func (r *Resolver) publishAutoPlay(roomID, userID int64, enabled bool) {
+	r.publishRoom(roomID, "auto_play_changed", map[string]any{
+		"roomID":  strconv.FormatInt(roomID, 10),
+		"userID":  strconv.FormatInt(userID, 10),
+		"enabled": enabled,
+	})
+}
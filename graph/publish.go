@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/ne241099/daifugo-server/graph/model"
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/internal/sse"
+	domain "github.com/ne241099/daifugo-server/model"
+)
+
+// publishLobby はロビーを購読している全員へイベントを配信する
+func (r *Resolver) publishLobby(eventType string, data any) {
+	if r.Hub != nil {
+		r.Hub.Publish(sse.LobbyTopic, eventType, data, nil)
+	}
+}
+
+// publishRoom は部屋のメンバーと観戦者へイベントを配信する
+func (r *Resolver) publishRoom(roomID int64, eventType string, data any) {
+	if r.Hub != nil {
+		r.Hub.Publish(sse.RoomTopic(roomID), eventType, data, nil)
+	}
+}
+
+// publishPlay はゲームの進行に関わるイベントを部屋へ配信する
+// 観戦者に遅れて全員の手札を見せるゲームでは、遅延表示と合わせて今の手札が分からないようメンバーだけに送る
+func (r *Resolver) publishPlay(room *domain.Room, eventType string, data any) {
+	if r.Hub == nil {
+		return
+	}
+	var to func(userID int64) bool
+	if room.Game != nil && room.Game.SpectatorDelay > 0 && !room.Game.IsFinished {
+		to = room.IsMember
+	}
+	r.Hub.PublishTo(sse.RoomTopic(room.ID), eventType, data, nil, to)
+}
+
+// publishRoomUpdated は部屋の変更をロビーと部屋の両方へ配信する
+// ロビーには誰でも届くため、手札を含むゲームの状態は外して送る
+func (r *Resolver) publishRoomUpdated(roomID int64, gqlRoom *model.Room) {
+	summary := *gqlRoom
+	summary.Game = nil
+	r.publishLobby("room_updated", &summary)
+	r.publishRoom(roomID, "room_updated", &summary)
+}
+
+// PublishGameUpdate はゲームの差分を部屋へ配信し、
+// 各プレイヤーには本人だけが見られる手札と交換の状態を送る
+func (r *Resolver) PublishGameUpdate(roomID int64) {
+	r.publishGameDelta(roomID)
+	r.publishPrivateState(roomID)
+}
+
+// privateState はプレイヤー本人宛てに送る手札と交換の状態
+type privateState struct {
+	userID   int64
+	hand     []*game.Card
+	exchange *game.Exchange
+}
+
+// publishPrivateState は人間のプレイヤーそれぞれに手札と返却待ちの交換を送る
+func (r *Resolver) publishPrivateState(roomID int64) {
+	if r.Hub == nil {
+		return
+	}
+
+	room, err := r.GetRoomUseCase.Execute(context.Background(), roomID)
+	if err != nil {
+		return
+	}
+
+	var states []privateState
+	if g := room.Game; g != nil {
+		for _, p := range g.Players {
+			if p.IsBot {
+				continue
+			}
+			st := privateState{userID: p.UserID, hand: slices.Clone(p.Hand)}
+			for _, ex := range g.PendingExchanges {
+				if ex.FromID == p.UserID {
+					exCopy := *ex
+					st.exchange = &exCopy
+				}
+			}
+			states = append(states, st)
+		}
+	}
+
+	rid := strconv.FormatInt(roomID, 10)
+	for _, st := range states {
+		topic := sse.UserTopic(st.userID)
+		r.Hub.Publish(topic, "hand_updated", map[string]any{
+			"roomID": rid,
+			"cards":  st.hand,
+		}, nil)
+		if st.exchange != nil {
+			r.Hub.Publish(topic, "exchange_pending", map[string]any{
+				"roomID":   rid,
+				"toUserID": strconv.FormatInt(st.exchange.ToID, 10),
+				"count":    st.exchange.Count,
+			}, nil)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package graph
 
 import (
+	"sync"
+	"time"
+
+	"github.com/ne241099/daifugo-server/internal/bot"
+	"github.com/ne241099/daifugo-server/internal/presence"
 	"github.com/ne241099/daifugo-server/internal/sse"
 	"github.com/ne241099/daifugo-server/usecase/game"
 	"github.com/ne241099/daifugo-server/usecase/room"
@@ -13,19 +18,49 @@ import (
 // here.
 
 type Resolver struct {
-	Hub                *sse.Hub
-	SignUpUseCase      user.SignUpUseCase
-	LoginUseCase       user.LoginUseCase
-	GetUserUseCase     user.GetUserUseCase
-	ListUsersUseCase   user.ListUsersUseCase
-	DeleteUserUseCase  user.DeleteUserUseCase
-	CreateRoomUseCase  room.CreateRoomUseCase
-	JoinRoomUseCase    room.JoinRoomUseCase
-	LeaveRoomUseCase   room.LeaveRoomUseCase
-	ListRoomsUseCase   room.ListRoomsUseCase
-	GetRoomUseCase     room.GetRoomUseCase
-	StartGameUseCase   *game.StartGameInteractor
-	RestartGameUseCase *game.RestartGameInteractor
-	PlayCardUseCase    *game.PlayCardInteractor
-	PassUseCase        *game.PassInteractor
+	Hub                        *sse.Hub
+	BotDriver                  *bot.Driver
+	SignUpUseCase              user.SignUpUseCase
+	LoginUseCase               user.LoginUseCase
+	GetUserUseCase             user.GetUserUseCase
+	ListUsersUseCase           user.ListUsersUseCase
+	DeleteUserUseCase          user.DeleteUserUseCase
+	CreateRoomUseCase          room.CreateRoomUseCase
+	JoinRoomUseCase            room.JoinRoomUseCase
+	LeaveRoomUseCase           room.LeaveRoomUseCase
+	ListRoomsUseCase           room.ListRoomsUseCase
+	ListUserRoomsUseCase       room.ListUserRoomsUseCase
+	ListDueRoomsUseCase        room.ListDueRoomsUseCase
+	GetRoomUseCase             room.GetRoomUseCase
+	AddBotUseCase              room.AddBotUseCase
+	RemoveBotUseCase           room.RemoveBotUseCase
+	SpectateRoomUseCase        room.SpectateRoomUseCase
+	StopSpectatingUseCase      room.StopSpectatingUseCase
+	UpdateSpectatorViewUseCase room.UpdateSpectatorViewUseCase
+	StartGameUseCase           *game.StartGameInteractor
+	RestartGameUseCase         *game.RestartGameInteractor
+	PlayCardUseCase            *game.PlayCardInteractor
+	PassUseCase                *game.PassInteractor
+	ResolveEffectUseCase       *game.ResolveEffectInteractor
+	ExchangeCardsUseCase       *game.ExchangeCardsInteractor
+	AutoExchangeUseCase        *game.AutoExchangeInteractor
+	TimeoutTurnUseCase         *game.TimeoutTurnInteractor
+	GetReplayUseCase           *game.GetReplayInteractor
+	GetGameRecordUseCase       *game.GetGameRecordInteractor
+	VerifyDealUseCase          *game.VerifyDealInteractor
+	ForceActionUseCase         *game.ForceActionInteractor
+	SetAutoPlayUseCase         *game.SetAutoPlayInteractor
+
+	// 接続状況の管理
+	Presence *presence.Tracker
+	// 切断したプレイヤーの入力待ちを代わりに進めるまでの時間（0なら何もしない）
+	DisconnectTimeout time.Duration
+	// 切断したプレイヤーの席をパスではなくボットの代打ちにするか
+	DisconnectToBot bool
+
+	// 部屋ごとに配信済みのゲームのイベントの通し番号
+	publishMu     sync.Mutex
+	publishedSeqs map[int64]int64
+	// 遅れて全員の手札を見せる観戦者へ配信済みの通し番号
+	delayedSeqs map[int64]int64
 }
@@ -9,11 +9,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/ne241099/daifugo-server/graph/model"
 	"github.com/ne241099/daifugo-server/internal/auth"
 	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/internal/sse"
 )
 
 // ID is the resolver for the id field.
@@ -31,15 +34,62 @@ func (r *cardResolver) Rank(ctx context.Context, obj *game.Card) (int32, error)
 	return int32(obj.Rank), nil
 }
 
+// Count is the resolver for the count field.
+func (r *exchangeResolver) Count(ctx context.Context, obj *game.Exchange) (int32, error) {
+	return int32(obj.Count), nil
+}
+
+// Phase is the resolver for the phase field.
+func (r *gameResolver) Phase(ctx context.Context, obj *game.Game) (model.GamePhase, error) {
+	return mapGamePhase(obj.Phase()), nil
+}
+
 // Turn is the resolver for the turn field.
 func (r *gameResolver) Turn(ctx context.Context, obj *game.Game) (int32, error) {
 	return int32(obj.Turn), nil
 }
 
+// CurrentPlayerID is the resolver for the currentPlayerID field.
+func (r *gameResolver) CurrentPlayerID(ctx context.Context, obj *game.Game) (*string, error) {
+	return optionalID(obj.CurrentPlayerID()), nil
+}
+
+// LastPlayerID is the resolver for the lastPlayerID field.
+func (r *gameResolver) LastPlayerID(ctx context.Context, obj *game.Game) (*string, error) {
+	return optionalID(obj.LastPlayerID), nil
+}
+
+// LastHandType is the resolver for the lastHandType field.
+func (r *gameResolver) LastHandType(ctx context.Context, obj *game.Game) (model.HandType, error) {
+	return mapHandType(obj.LastHandType), nil
+}
+
+// MiyakoOchiPlayerID is the resolver for the miyakoOchiPlayerID field.
+func (r *gameResolver) MiyakoOchiPlayerID(ctx context.Context, obj *game.Game) (*string, error) {
+	if obj.MiyakoOchiPlayer == nil {
+		return nil, nil
+	}
+	return optionalID(obj.MiyakoOchiPlayer.UserID), nil
+}
+
+// LockedSuits is the resolver for the lockedSuits field.
+func (r *gameResolver) LockedSuits(ctx context.Context, obj *game.Game) ([]string, error) {
+	suits := make([]string, len(obj.LockedSuits))
+	for i, s := range obj.LockedSuits {
+		suits[i] = s.String()
+	}
+	return suits, nil
+}
+
+// Direction is the resolver for the direction field.
+func (r *gameResolver) Direction(ctx context.Context, obj *game.Game) (int32, error) {
+	return int32(obj.Direction), nil
+}
+
 // Players is the resolver for the players field.
-func (r *gameResolver) Players(ctx context.Context, obj *game.Game) ([]*game.Player, error) {
+func (r *gameResolver) Players(ctx context.Context, obj *game.Game) ([]*model.GamePlayer, error) {
 	if obj == nil || obj.Players == nil {
-		return []*game.Player{}, nil
+		return []*model.GamePlayer{}, nil
 	}
 
 	currentUserID, err := auth.GetUserID(ctx)
@@ -47,51 +97,73 @@ func (r *gameResolver) Players(ctx context.Context, obj *game.Game) ([]*game.Pla
 		return nil, fmt.Errorf("unauthorized: %w", err)
 	}
 
-	// 1. 自分がプレイヤーに含まれているか確認（観戦者判定）
-	isSpectator := true
-	for _, p := range obj.Players {
-		if p != nil && p.UserID == currentUserID {
-			isSpectator = false
-			break
-		}
-	}
-
-	// 2. プレイヤーごとの手札をフィルタリングして返す
-	result := make([]*game.Player, 0, len(obj.Players))
+	// 手札や出せる手は、見ている人に合わせて GamePlayer のフィールドで決める
+	return mapGamePlayers(obj, obj.Players, currentUserID), nil
+}
 
-	for _, p := range obj.Players {
-		// nilチェック (パニック防止)
-		if p == nil {
-			continue
-		}
+// FinishedPlayers is the resolver for the finishedPlayers field.
+func (r *gameResolver) FinishedPlayers(ctx context.Context, obj *game.Game) ([]*model.GamePlayer, error) {
+	currentUserID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	return mapGamePlayers(obj, obj.FinishedPlayers, currentUserID), nil
+}
 
-		// 構造体のコピーを作成
-		pCopy := *p
+// PassCount is the resolver for the passCount field.
+func (r *gameResolver) PassCount(ctx context.Context, obj *game.Game) (int32, error) {
+	return int32(obj.PassCount), nil
+}
 
-		// 表示条件:
-		// A. ゲームが終了している
-		// B. 自分が観戦者である
-		// C. 自分の手札である
-		isVisible := obj.IsFinished || isSpectator || (p.UserID == currentUserID)
+// ExchangeDeadline is the resolver for the exchangeDeadline field.
+func (r *gameResolver) ExchangeDeadline(ctx context.Context, obj *game.Game) (*time.Time, error) {
+	if obj.ExchangeDeadline.IsZero() {
+		return nil, nil
+	}
+	return &obj.ExchangeDeadline, nil
+}
 
-		if !isVisible {
-			// 条件を満たさない場合、手札を隠す
-			pCopy.Hand = []*game.Card{}
-		}
+// TurnDeadline is the resolver for the turnDeadline field.
+func (r *gameResolver) TurnDeadline(ctx context.Context, obj *game.Game) (*time.Time, error) {
+	if obj.TurnDeadline.IsZero() {
+		return nil, nil
+	}
+	return &obj.TurnDeadline, nil
+}
 
-		result = append(result, &pCopy)
+// Events is the resolver for the events field.
+func (r *gameResolver) Events(ctx context.Context, obj *game.Game, since *int32) ([]*model.GameEvent, error) {
+	var seq int64
+	if since != nil {
+		seq = int64(*since)
 	}
+	return mapGameEvents(obj.EventsSince(seq)), nil
+}
 
-	return result, nil
+// RecordID is the resolver for the recordID field.
+func (r *gameResolver) RecordID(ctx context.Context, obj *game.Game) (*string, error) {
+	return optionalID(obj.RecordID), nil
 }
 
-// PassCount is the resolver for the passCount field.
-func (r *gameResolver) PassCount(ctx context.Context, obj *game.Game) (int32, error) {
-	return int32(obj.PassCount), nil
+// Seed is the resolver for the seed field.
+func (r *gameResolver) Seed(ctx context.Context, obj *game.Game) (*string, error) {
+	// 種は終了するまで公開しない
+	if !obj.IsFinished && !obj.IsReplay || obj.Seed == "" {
+		return nil, nil
+	}
+	return &obj.Seed, nil
 }
 
 // User is the resolver for the user field.
-func (r *gamePlayerResolver) User(ctx context.Context, obj *game.Player) (*model.User, error) {
+func (r *gamePlayerResolver) User(ctx context.Context, obj *model.GamePlayer) (*model.User, error) {
+	// ボットはユーザー登録されていないので、席の情報から返す
+	if obj.IsBot {
+		return &model.User{
+			ID:   strconv.FormatInt(obj.UserID, 10),
+			Name: obj.Name,
+		}, nil
+	}
+
 	u, err := r.GetUserUseCase.Execute(ctx, obj.UserID)
 	if err != nil {
 		return nil, err
@@ -106,11 +178,66 @@ func (r *gamePlayerResolver) User(ctx context.Context, obj *game.Player) (*model
 	}, nil
 }
 
+// Hand is the resolver for the hand field.
+func (r *gamePlayerResolver) Hand(ctx context.Context, obj *model.GamePlayer) ([]*game.Card, error) {
+	// 表示条件:
+	// A. ゲームが終了している
+	// B. 自分の手札である
+	// C. 全員の手札を公開する表示（棋譜の再生・観戦者向けの遅延表示）
+	g := obj.Game
+	if g.IsFinished || obj.UserID == obj.ViewerID || g.RevealHands {
+		return obj.Player.Hand, nil
+	}
+	// 条件を満たさない場合、手札を隠す
+	return []*game.Card{}, nil
+}
+
+// HandCount is the resolver for the handCount field.
+func (r *gamePlayerResolver) HandCount(ctx context.Context, obj *model.GamePlayer) (int32, error) {
+	// 手札を隠した場合も実際の枚数を返す
+	return int32(len(obj.Player.Hand)), nil
+}
+
 // Rank is the resolver for the rank field.
-func (r *gamePlayerResolver) Rank(ctx context.Context, obj *game.Player) (int32, error) {
+func (r *gamePlayerResolver) Rank(ctx context.Context, obj *model.GamePlayer) (int32, error) {
 	return int32(obj.Rank), nil
 }
 
+// FinishReason is the resolver for the finishReason field.
+func (r *gamePlayerResolver) FinishReason(ctx context.Context, obj *model.GamePlayer) (*string, error) {
+	if obj.FinishReason == "" {
+		return nil, nil
+	}
+	return &obj.FinishReason, nil
+}
+
+// LegalMoves is the resolver for the legalMoves field.
+func (r *gamePlayerResolver) LegalMoves(ctx context.Context, obj *model.GamePlayer) ([][]*game.Card, error) {
+	// 出せる手は本人にだけ返す
+	if obj.UserID != obj.ViewerID {
+		return nil, nil
+	}
+	return obj.Game.LegalMoves(obj.UserID), nil
+}
+
+// IsOnline is the resolver for the isOnline field.
+func (r *gamePlayerResolver) IsOnline(ctx context.Context, obj *model.GamePlayer) (bool, error) {
+	if obj.IsBot {
+		return true, nil
+	}
+	online, _ := r.presenceOf(obj.UserID)
+	return online, nil
+}
+
+// LastSeenAt is the resolver for the lastSeenAt field.
+func (r *gamePlayerResolver) LastSeenAt(ctx context.Context, obj *model.GamePlayer) (*time.Time, error) {
+	if obj.IsBot {
+		return nil, nil
+	}
+	_, lastSeen := r.presenceOf(obj.UserID)
+	return lastSeen, nil
+}
+
 // SignUp is the resolver for the signUp field.
 func (r *mutationResolver) SignUp(ctx context.Context, in model.SignUpInput) (*model.User, error) {
 	u, err := r.SignUpUseCase.Execute(ctx, in)
@@ -128,14 +255,14 @@ func (r *mutationResolver) SignUp(ctx context.Context, in model.SignUpInput) (*m
 
 // CreateRoom is the resolver for the createRoom field.
 // 部屋を作成する
-func (r *mutationResolver) CreateRoom(ctx context.Context, name string) (*model.Room, error) {
+func (r *mutationResolver) CreateRoom(ctx context.Context, name string, rules *model.RuleSetInput) (*model.Room, error) {
 	ownerID, err := auth.GetUserID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
 	}
 
 	// UseCaseを実行
-	createdRoom, err := r.CreateRoomUseCase.Execute(ctx, name, ownerID)
+	createdRoom, err := r.CreateRoomUseCase.Execute(ctx, name, ownerID, mapRuleSetInput(rules))
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +270,9 @@ func (r *mutationResolver) CreateRoom(ctx context.Context, name string) (*model.
 	// ドメインモデル から GraphQLモデル への変換
 	gqlRoom := mapRoomToGraphQL(createdRoom)
 
-	r.Hub.Publish("room_created", gqlRoom, nil)
+	summary := *gqlRoom
+	summary.Game = nil
+	r.publishLobby("room_created", &summary)
 	return gqlRoom, nil
 }
 
@@ -171,7 +300,7 @@ func (r *mutationResolver) JoinRoom(ctx context.Context, roomID string) (*model.
 
 	gqlRoom := mapRoomToGraphQL(joinedRoom)
 
-	r.Hub.Publish("room_updated", gqlRoom, nil)
+	r.publishRoomUpdated(rID, gqlRoom)
 	return gqlRoom, nil
 }
 
@@ -185,9 +314,14 @@ func (r *mutationResolver) StartGame(ctx context.Context, roomID string) (*model
 		return nil, err
 	}
 
-	if r.Hub != nil {
-		r.Hub.Publish(roomID, "game_started", nil)
-	}
+	r.publishRoom(rid, "game_started", map[string]any{"roomID": roomID})
+	// 配り方を後から検証できるよう、種のハッシュを先に公開する
+	r.publishRoom(rid, "deal_committed", map[string]any{
+		"roomID":   roomID,
+		"seedHash": room.Game.SeedHash,
+	})
+	r.PublishGameUpdate(rid)
+	r.triggerBots(rid)
 
 	return mapRoomToGraphQL(room), nil
 }
@@ -213,9 +347,18 @@ func (r *mutationResolver) PlayCard(ctx context.Context, roomID string, cardIDs
 		return nil, err
 	}
 
-	if r.Hub != nil {
-		r.Hub.Publish(roomID, "game_update", nil)
+	r.PublishGameUpdate(rid)
+
+	// カード選択待ちになった場合は通知する
+	if effect := room.Game.PendingEffect; effect != nil {
+		r.publishPlay(room, "effect_pending", map[string]any{
+			"roomID": roomID,
+			"userID": strconv.FormatInt(effect.UserID, 10),
+			"type":   effect.Type.String(),
+			"count":  effect.Count,
+		})
 	}
+	r.triggerBots(rid)
 
 	return mapRoomToGraphQL(room), nil
 }
@@ -236,10 +379,63 @@ func (r *mutationResolver) Pass(ctx context.Context, roomID string) (*model.Room
 		return nil, err
 	}
 
-	if r.Hub != nil {
-		r.Hub.Publish(roomID, "game_update", nil)
+	r.PublishGameUpdate(rid)
+	r.triggerBots(rid)
+
+	return mapRoomToGraphQL(room), nil
+}
+
+// ResolveEffect is the resolver for the resolveEffect field.
+func (r *mutationResolver) ResolveEffect(ctx context.Context, roomID string, cardIDs []int32) (*model.Room, error) {
+	rid, _ := strconv.ParseInt(roomID, 10, 64)
+
+	// 実行ユーザーを取得
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
 	}
 
+	targetCardIDs := make([]int, len(cardIDs))
+	for i, id := range cardIDs {
+		targetCardIDs[i] = int(id)
+	}
+
+	// UseCaseを実行
+	room, err := r.ResolveEffectUseCase.Execute(ctx, rid, userID, targetCardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	r.PublishGameUpdate(rid)
+	r.triggerBots(rid)
+
+	return mapRoomToGraphQL(room), nil
+}
+
+// ExchangeCards is the resolver for the exchangeCards field.
+func (r *mutationResolver) ExchangeCards(ctx context.Context, roomID string, cardIDs []int32) (*model.Room, error) {
+	rid, _ := strconv.ParseInt(roomID, 10, 64)
+
+	// 実行ユーザーを取得
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+
+	targetCardIDs := make([]int, len(cardIDs))
+	for i, id := range cardIDs {
+		targetCardIDs[i] = int(id)
+	}
+
+	// UseCaseを実行
+	room, err := r.ExchangeCardsUseCase.Execute(ctx, rid, userID, targetCardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	r.PublishGameUpdate(rid)
+	r.triggerBots(rid)
+
 	return mapRoomToGraphQL(room), nil
 }
 
@@ -258,13 +454,149 @@ func (r *mutationResolver) LeaveRoom(ctx context.Context, roomID string) (bool,
 		return false, err
 	}
 
-	r.Hub.Publish("room_updated", map[string]any{
+	left := map[string]any{
 		"roomID": roomID,
 		"event":  "member_left",
-	}, nil)
+		"userID": strconv.FormatInt(userID, 10),
+	}
+	r.publishLobby("room_updated", left)
+	r.publishRoom(rid, "room_updated", left)
+	// 退出した部屋のイベントは届かないようにする
+	if r.Hub != nil {
+		r.Hub.Leave(sse.RoomTopic(rid), userID)
+	}
+	r.triggerBots(rid)
 	return true, nil
 }
 
+// AddBot is the resolver for the addBot field.
+func (r *mutationResolver) AddBot(ctx context.Context, roomID string) (*model.Room, error) {
+	rid, err := strconv.ParseInt(roomID, 10, 64)
+	if err != nil {
+		return nil, errors.Join(err)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+
+	room, err := r.AddBotUseCase.Execute(ctx, rid, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlRoom := mapRoomToGraphQL(room)
+
+	r.publishRoomUpdated(rid, gqlRoom)
+	return gqlRoom, nil
+}
+
+// RemoveBot is the resolver for the removeBot field.
+func (r *mutationResolver) RemoveBot(ctx context.Context, roomID string, botID string) (*model.Room, error) {
+	rid, err := strconv.ParseInt(roomID, 10, 64)
+	if err != nil {
+		return nil, errors.Join(err)
+	}
+	bid, err := strconv.ParseInt(botID, 10, 64)
+	if err != nil {
+		return nil, errors.Join(err)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+
+	room, err := r.RemoveBotUseCase.Execute(ctx, rid, userID, bid)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlRoom := mapRoomToGraphQL(room)
+
+	r.publishRoomUpdated(rid, gqlRoom)
+	return gqlRoom, nil
+}
+
+// SpectateRoom is the resolver for the spectateRoom field.
+func (r *mutationResolver) SpectateRoom(ctx context.Context, roomID string) (*model.Room, error) {
+	rid, err := strconv.ParseInt(roomID, 10, 64)
+	if err != nil {
+		return nil, errors.Join(err)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+
+	room, err := r.SpectateRoomUseCase.Execute(ctx, rid, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlRoom := mapRoomToGraphQL(room)
+
+	r.publishRoomUpdated(rid, gqlRoom)
+	return gqlRoom, nil
+}
+
+// StopSpectating is the resolver for the stopSpectating field.
+func (r *mutationResolver) StopSpectating(ctx context.Context, roomID string) (*model.Room, error) {
+	rid, err := strconv.ParseInt(roomID, 10, 64)
+	if err != nil {
+		return nil, errors.Join(err)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+
+	room, err := r.StopSpectatingUseCase.Execute(ctx, rid, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlRoom := mapRoomToGraphQL(room)
+
+	r.publishRoomUpdated(rid, gqlRoom)
+	if r.Hub != nil {
+		r.Hub.Leave(sse.RoomTopic(rid), userID)
+	}
+	return gqlRoom, nil
+}
+
+// SetSpectatorView is the resolver for the setSpectatorView field.
+func (r *mutationResolver) SetSpectatorView(ctx context.Context, roomID string, fullView bool, delay *int32) (*model.Room, error) {
+	var d *int
+	if delay != nil {
+		v := int(*delay)
+		d = &v
+	}
+
+	rid, err := strconv.ParseInt(roomID, 10, 64)
+	if err != nil {
+		return nil, errors.Join(err)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+
+	room, err := r.UpdateSpectatorViewUseCase.Execute(ctx, rid, userID, fullView, d)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlRoom := mapRoomToGraphQL(room)
+
+	r.publishRoomUpdated(rid, gqlRoom)
+	return gqlRoom, nil
+}
+
 // RestartGame is the resolver for the restartGame field.
 func (r *mutationResolver) RestartGame(ctx context.Context, roomID string) (*model.Room, error) {
 	rid, err := strconv.ParseInt(roomID, 10, 64)
@@ -277,6 +609,8 @@ func (r *mutationResolver) RestartGame(ctx context.Context, roomID string) (*mod
 		return nil, err
 	}
 
+	r.PublishGameUpdate(rid)
+
 	return mapRoomToGraphQL(room), nil
 }
 
@@ -314,9 +648,19 @@ func (r *mutationResolver) Login(ctx context.Context, email string, password str
 	}, nil
 }
 
+// Type is the resolver for the type field.
+func (r *pendingEffectResolver) Type(ctx context.Context, obj *game.PendingEffect) (string, error) {
+	return obj.Type.String(), nil
+}
+
+// Count is the resolver for the count field.
+func (r *pendingEffectResolver) Count(ctx context.Context, obj *game.PendingEffect) (int32, error) {
+	return int32(obj.Count), nil
+}
+
 // Hello is the resolver for the hello field.
 func (r *queryResolver) Hello(ctx context.Context) (string, error) {
-	r.Hub.Publish("Hello", map[string]any{"message": "Someone queried hello!"}, nil)
+	r.publishLobby("Hello", map[string]any{"message": "Someone queried hello!"})
 	return "hello", nil
 }
 
@@ -336,14 +680,27 @@ func (r *queryResolver) Rooms(ctx context.Context) ([]*model.Room, error) {
 		for j, mid := range room.MemberIDs {
 			memberIDsStr[j] = strconv.FormatInt(mid, 10)
 		}
+		botIDsStr := make([]string, len(room.BotIDs))
+		for j, bid := range room.BotIDs {
+			botIDsStr[j] = strconv.FormatInt(bid, 10)
+		}
+		spectatorIDsStr := make([]string, len(room.SpectatorIDs))
+		for j, sid := range room.SpectatorIDs {
+			spectatorIDsStr[j] = strconv.FormatInt(sid, 10)
+		}
 
 		gqlRooms[i] = &model.Room{
-			ID:        strconv.FormatInt(room.ID, 10),
-			Name:      room.Name,
-			OwnerID:   strconv.FormatInt(room.OwnerID, 10),
-			MemberIDs: memberIDsStr,
-			CreatedAt: room.CreatedAt,
-			UpdatedAt: room.UpdatedAt,
+			ID:                strconv.FormatInt(room.ID, 10),
+			Name:              room.Name,
+			OwnerID:           strconv.FormatInt(room.OwnerID, 10),
+			MemberIDs:         memberIDsStr,
+			BotIDs:            botIDsStr,
+			Rules:             &room.Rules,
+			CreatedAt:         room.CreatedAt,
+			UpdatedAt:         room.UpdatedAt,
+			SpectatorIDs:      spectatorIDsStr,
+			SpectatorFullView: room.SpectatorFullView,
+			SpectatorDelay:    int32(room.SpectatorDelay),
 		}
 	}
 
@@ -427,6 +784,60 @@ func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
 	}, nil
 }
 
+// Replay is the resolver for the replay field.
+func (r *queryResolver) Replay(ctx context.Context, id string, step *int32) (*model.Replay, error) {
+	recordID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid record id: %w", err)
+	}
+
+	var s *int
+	if step != nil {
+		v := int(*step)
+		s = &v
+	}
+
+	record, g, actual, err := r.GetReplayUseCase.Execute(ctx, recordID, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Replay{
+		ID:         strconv.FormatInt(record.ID, 10),
+		RoomID:     strconv.FormatInt(record.RoomID, 10),
+		Step:       int32(actual),
+		TotalSteps: int32(len(record.Record.Actions)),
+		FinishedAt: record.FinishedAt,
+		Game:       g,
+	}, nil
+}
+
+// VerifyDeal is the resolver for the verifyDeal field.
+func (r *queryResolver) VerifyDeal(ctx context.Context, recordID string) (*model.DealVerification, error) {
+	rid, err := strconv.ParseInt(recordID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid record id: %w", err)
+	}
+
+	result, err := r.VerifyDealUseCase.Execute(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &model.DealVerification{
+		RecordID: strconv.FormatInt(result.Record.ID, 10),
+		Seed:     result.Record.Record.Seed,
+		SeedHash: result.Record.Record.SeedHash,
+		Valid:    result.Mismatch == nil,
+		Hands:    result.Hands,
+	}
+	if result.Mismatch != nil {
+		reason := result.Mismatch.Error()
+		verification.Reason = &reason
+	}
+	return verification, nil
+}
+
 // Owner is the resolver for the owner field.
 func (r *roomResolver) Owner(ctx context.Context, obj *model.Room) (*model.User, error) {
 	ownerID, err := strconv.ParseInt(obj.OwnerID, 10, 64)
@@ -471,9 +882,106 @@ func (r *roomResolver) Members(ctx context.Context, obj *model.Room) ([]*model.U
 	return gqlUsers, nil
 }
 
+// Game is the resolver for the game field.
+func (r *roomResolver) Game(ctx context.Context, obj *model.Room) (*game.Game, error) {
+	g := obj.Game
+	if g == nil || g.IsFinished || g.SpectatorDelay == 0 {
+		return g, nil
+	}
+
+	// オーナーが許可した場合、観戦者には遅れて全員の手札を見せる
+	// 設定はゲームの開始時のものを使う（途中で切り替えても進行中のゲームには反映しない）
+	userID, err := auth.GetUserID(ctx)
+	if err != nil || !slices.Contains(obj.SpectatorIDs, strconv.FormatInt(userID, 10)) {
+		return g, nil
+	}
+	return g.DelayedView(g.SpectatorDelay)
+}
+
+// JokerCount is the resolver for the jokerCount field.
+func (r *ruleSetResolver) JokerCount(ctx context.Context, obj *game.RuleSet) (int32, error) {
+	return int32(obj.JokerCount), nil
+}
+
+// TurnTimeLimit is the resolver for the turnTimeLimit field.
+func (r *ruleSetResolver) TurnTimeLimit(ctx context.Context, obj *game.RuleSet) (int32, error) {
+	return int32(obj.TurnTimeLimit), nil
+}
+
+// RoomUpdated is the resolver for the roomUpdated field.
+func (r *subscriptionResolver) RoomUpdated(ctx context.Context, roomID string) (<-chan *model.Room, error) {
+	rid, err := strconv.ParseInt(roomID, 10, 64)
+	if err != nil {
+		return nil, errors.Join(err)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+
+	if err := r.authorizeRoom(ctx, rid, userID); err != nil {
+		return nil, err
+	}
+
+	return r.streamRoom(ctx, rid, r.subscribeTopic(ctx, userID, sse.RoomTopic(rid))), nil
+}
+
+// GameEvents is the resolver for the gameEvents field.
+func (r *subscriptionResolver) GameEvents(ctx context.Context, roomID string) (<-chan *model.GameEvent, error) {
+	rid, err := strconv.ParseInt(roomID, 10, 64)
+	if err != nil {
+		return nil, errors.Join(err)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+
+	if err := r.authorizeRoom(ctx, rid, userID); err != nil {
+		return nil, err
+	}
+
+	return r.streamGameEvents(ctx, r.subscribeTopic(ctx, userID, sse.RoomTopic(rid))), nil
+}
+
+// LobbyUpdated is the resolver for the lobbyUpdated field.
+func (r *subscriptionResolver) LobbyUpdated(ctx context.Context) (<-chan *model.LobbyEvent, error) {
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+
+	return r.streamLobby(ctx, r.subscribeTopic(ctx, userID, sse.LobbyTopic)), nil
+}
+
+// IsOnline is the resolver for the isOnline field.
+func (r *userResolver) IsOnline(ctx context.Context, obj *model.User) (bool, error) {
+	uid, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	online, _ := r.presenceOf(uid)
+	return online, nil
+}
+
+// LastSeenAt is the resolver for the lastSeenAt field.
+func (r *userResolver) LastSeenAt(ctx context.Context, obj *model.User) (*time.Time, error) {
+	uid, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	_, lastSeen := r.presenceOf(uid)
+	return lastSeen, nil
+}
+
 // Card returns CardResolver implementation.
 func (r *Resolver) Card() CardResolver { return &cardResolver{r} }
 
+// Exchange returns ExchangeResolver implementation.
+func (r *Resolver) Exchange() ExchangeResolver { return &exchangeResolver{r} }
+
 // Game returns GameResolver implementation.
 func (r *Resolver) Game() GameResolver { return &gameResolver{r} }
 
@@ -483,15 +991,32 @@ func (r *Resolver) GamePlayer() GamePlayerResolver { return &gamePlayerResolver{
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
+// PendingEffect returns PendingEffectResolver implementation.
+func (r *Resolver) PendingEffect() PendingEffectResolver { return &pendingEffectResolver{r} }
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
 // Room returns RoomResolver implementation.
 func (r *Resolver) Room() RoomResolver { return &roomResolver{r} }
 
+// RuleSet returns RuleSetResolver implementation.
+func (r *Resolver) RuleSet() RuleSetResolver { return &ruleSetResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
 type cardResolver struct{ *Resolver }
+type exchangeResolver struct{ *Resolver }
 type gameResolver struct{ *Resolver }
 type gamePlayerResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type pendingEffectResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type roomResolver struct{ *Resolver }
+type ruleSetResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
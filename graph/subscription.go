@@ -0,0 +1,181 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/ne241099/daifugo-server/graph/model"
+	"github.com/ne241099/daifugo-server/internal/sse"
+)
+
+// authorizeRoom は部屋のメンバーか観戦者だけに部屋の購読を許可する
+func (r *Resolver) authorizeRoom(ctx context.Context, roomID, userID int64) error {
+	room, err := r.GetRoomUseCase.Execute(ctx, roomID)
+	if err != nil {
+		return err
+	}
+
+	if !room.IsMember(userID) && !room.IsSpectator(userID) {
+		return errors.New("部屋に参加していません")
+	}
+	return nil
+}
+
+// subscribeTopic は Hub のトピックを購読し、ctx が終わったら購読を解除する
+// トピックのイベントだけを流し、購読が解除されると閉じるチャネルを返す
+func (r *Resolver) subscribeTopic(ctx context.Context, userID int64, topic string) <-chan sse.Event {
+	c := r.Hub.Subscribe(userID, topic)
+	out := make(chan sse.Event, 32)
+
+	go func() {
+		<-ctx.Done()
+		r.Hub.Unsubscribe(c)
+	}()
+
+	go func() {
+		defer close(out)
+		for ev := range c.Events() {
+			if ev.Topic != topic {
+				continue
+			}
+			select {
+			case out <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// streamRoom は部屋のイベントが届くたびに最新の部屋を送る
+// 手札の見え方は通常の room クエリと同じリゾルバーで決まる
+func (r *Resolver) streamRoom(ctx context.Context, roomID int64, events <-chan sse.Event) <-chan *model.Room {
+	ch := make(chan *model.Room, 1)
+
+	send := func() bool {
+		room, err := r.GetRoomUseCase.Execute(ctx, roomID)
+		if err != nil {
+			return false // 部屋が削除された
+		}
+		select {
+		case ch <- mapRoomToGraphQL(room):
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(ch)
+
+		if !send() {
+			return
+		}
+		for range events {
+			// 続けて届いたイベントはまとめて1回で送る
+			if !drain(events) {
+				return
+			}
+			if !send() {
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// streamGameEvents は game_update に含まれる出来事を1つずつ送る
+func (r *Resolver) streamGameEvents(ctx context.Context, events <-chan sse.Event) <-chan *model.GameEvent {
+	ch := make(chan *model.GameEvent, 8)
+
+	go func() {
+		defer close(ch)
+
+		var lastSeq int64
+		for ev := range events {
+			update, ok := ev.Data.(*GameUpdate)
+			if !ok {
+				continue
+			}
+			// 新しいゲームで通し番号が振り直された
+			if update.Seq < lastSeq {
+				lastSeq = 0
+			}
+			for _, gEvent := range update.Events {
+				if int64(gEvent.Seq) <= lastSeq {
+					continue
+				}
+				select {
+				case ch <- gEvent:
+					lastSeq = int64(gEvent.Seq)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+// streamLobby はロビーの部屋の作成・更新を送る
+func (r *Resolver) streamLobby(ctx context.Context, events <-chan sse.Event) <-chan *model.LobbyEvent {
+	ch := make(chan *model.LobbyEvent, 8)
+
+	go func() {
+		defer close(ch)
+
+		for ev := range events {
+			roomID, ok := lobbyRoomID(ev.Data)
+			if !ok {
+				continue
+			}
+
+			lobbyEvent := &model.LobbyEvent{Type: ev.Type, RoomID: roomID}
+			if rid, err := strconv.ParseInt(roomID, 10, 64); err == nil {
+				if room, err := r.GetRoomUseCase.Execute(ctx, rid); err == nil {
+					lobbyEvent.Room = mapRoomToGraphQL(room)
+				}
+			}
+
+			select {
+			case ch <- lobbyEvent:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// lobbyRoomID はロビーのイベントの内容から部屋のIDを取り出す
+func lobbyRoomID(data any) (string, bool) {
+	switch d := data.(type) {
+	case *model.Room:
+		return d.ID, true
+	case map[string]any:
+		id, ok := d["roomID"].(string)
+		return id, ok
+	default:
+		return "", false
+	}
+}
+
+// drain は受信済みのイベントを読み捨てる。チャネルが閉じていれば false を返す
+func drain(events <-chan sse.Event) bool {
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return false
+			}
+		default:
+			return true
+		}
+	}
+}
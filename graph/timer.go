@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	domain "github.com/ne241099/daifugo-server/model"
+)
+
+// WatchTurnDeadlines は定期的に各部屋のカード交換・手番の期限と切断中のプレイヤーを確認し、必要なら自動で進める
+// ボットの行動など、リゾルバーを経由しない操作の後の期限や、再起動・別のサーバーで始まったゲームの期限も拾うためにポーリングする
+func (r *Resolver) WatchTurnDeadlines(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.advanceDueRooms(ctx, now)
+		}
+	}
+}
+
+// advanceDueRooms は期限が来た部屋と切断したプレイヤーがいる部屋だけを1回で読み込み、自動で進める
+// 待機中の部屋や終了した部屋は読み込まない
+func (r *Resolver) advanceDueRooms(ctx context.Context, now time.Time) {
+	rooms, err := r.ListDueRoomsUseCase.Execute(ctx, now, r.disconnectedUsers(now))
+	if err != nil {
+		return
+	}
+
+	for _, room := range rooms {
+		// 期限切れで進めた場合、切断の確認は次の確認で最新の状態に対して行う
+		if r.expireTurn(ctx, room, now) {
+			continue
+		}
+		r.handleDisconnected(ctx, room, now)
+	}
+}
+
+// expireTurn はカード交換・手番の期限が過ぎていれば自動で進める。進めた場合は true を返す
+func (r *Resolver) expireTurn(ctx context.Context, room *domain.Room, now time.Time) bool {
+	g := room.Game
+	if g == nil || g.IsFinished {
+		return false
+	}
+
+	// カード交換の期限（手番の制限時間がなくても交換は自動で完了させる）
+	if g.IsExchanging() && !g.ExchangeDeadline.IsZero() && !now.Before(g.ExchangeDeadline) {
+		_, changed, err := r.AutoExchangeUseCase.Execute(ctx, room.ID)
+		if err != nil || !changed {
+			return false
+		}
+		r.PublishGameUpdate(room.ID)
+		r.triggerBots(room.ID)
+		return true
+	}
+
+	if g.TurnDeadline.IsZero() || now.Before(g.TurnDeadline) {
+		return false
+	}
+
+	_, userIDs, err := r.TimeoutTurnUseCase.Execute(ctx, room.ID)
+	if err != nil || len(userIDs) == 0 {
+		return false
+	}
+
+	r.publishPlay(room, "turn_expired", map[string]any{
+		"roomID":  strconv.FormatInt(room.ID, 10),
+		"userIDs": userIDs,
+	})
+	r.PublishGameUpdate(room.ID)
+	r.triggerBots(room.ID)
+	return true
+}
@@ -0,0 +1,93 @@
+package inmem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+var _ repository.GameRecordRepository = &InmemGameRecordRepository{}
+
+// InmemGameRecordRepository は記録をJSONで保持する（保存後の変更の影響を受けない）
+type InmemGameRecordRepository struct {
+	mtx  sync.RWMutex
+	data map[int64][]byte
+	next int64
+	// 部屋とゲームごとの記録のID（SaveGameRecordOnce 用）
+	games map[gameKey]int64
+}
+
+type gameKey struct {
+	roomID   int64
+	seedHash string
+}
+
+func NewInmemGameRecordRepository() *InmemGameRecordRepository {
+	return &InmemGameRecordRepository{
+		data:  make(map[int64][]byte),
+		next:  1,
+		games: make(map[gameKey]int64),
+	}
+}
+
+func (r *InmemGameRecordRepository) SaveGameRecord(ctx context.Context, record *model.GameRecord) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r.save(record)
+}
+
+func (r *InmemGameRecordRepository) SaveGameRecordOnce(ctx context.Context, record *model.GameRecord) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if record.Record == nil || record.Record.SeedHash == "" {
+		return r.save(record)
+	}
+
+	key := gameKey{roomID: record.RoomID, seedHash: record.Record.SeedHash}
+	if id, ok := r.games[key]; ok {
+		record.ID = id
+		return nil
+	}
+	if err := r.save(record); err != nil {
+		return err
+	}
+	r.games[key] = record.ID
+	return nil
+}
+
+func (r *InmemGameRecordRepository) save(record *model.GameRecord) error {
+	// 新規作成の場合はIDを割り当て
+	if record.ID == 0 {
+		record.ID = r.next
+		r.next++
+	}
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode game record: %w", err)
+	}
+	r.data[record.ID] = b
+	return nil
+}
+
+func (r *InmemGameRecordRepository) GetGameRecordByID(ctx context.Context, id int64) (*model.GameRecord, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	b, ok := r.data[id]
+	if !ok {
+		return nil, repository.ErrEntityNotFound
+	}
+
+	var record model.GameRecord
+	if err := json.Unmarshal(b, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode game record: %w", err)
+	}
+	return &record, nil
+}
@@ -3,7 +3,9 @@ package inmem
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -14,6 +16,8 @@ import (
 
 var _ repository.RoomRepository = &InmemRoomRepository{}
 
+// InmemRoomRepository は部屋をメモリに保存する
+// 保存・取得のたびに複製するため、呼び出し側の変更は SaveRoom するまで他に見えない
 type InmemRoomRepository struct {
 	mtx  sync.RWMutex
 	data map[int64]*model.Room
@@ -47,11 +51,25 @@ func (r *InmemRoomRepository) SaveRoom(ctx context.Context, room *model.Room) er
 	if room.ID == 0 {
 		room.ID = r.next
 		r.next++
+	} else {
+		stored, ok := r.data[room.ID]
+		if !ok {
+			return repository.ErrEntityNotFound
+		}
+		// 読み込んだ後に他の更新が保存されていた
+		if stored.Version != room.Version {
+			return repository.ErrConflict
+		}
 	}
 
+	room.Version++
 	room.UpdatedAt = time.Now()
 
-	r.data[room.ID] = room
+	safeCopy := r.jsonDeepCopy(room)
+	if safeCopy == nil {
+		return errors.New("failed to copy room")
+	}
+	r.data[room.ID] = safeCopy
 	return nil
 }
 
@@ -74,6 +92,45 @@ func (r *InmemRoomRepository) ListRooms(ctx context.Context) ([]*model.Room, err
 	return rooms, nil
 }
 
+func (r *InmemRoomRepository) ListRoomsByUserID(ctx context.Context, userID int64) ([]*model.Room, error) {
+	return r.listWhere(func(room *model.Room) bool {
+		return room.IsMember(userID) || room.IsSpectator(userID)
+	}), nil
+}
+
+func (r *InmemRoomRepository) ListDueRooms(ctx context.Context, now time.Time, userIDs []int64) ([]*model.Room, error) {
+	return r.listWhere(func(room *model.Room) bool {
+		if !room.IsPlaying() {
+			return false
+		}
+		if deadline := room.NextDeadline(); !deadline.IsZero() && !now.Before(deadline) {
+			return true
+		}
+		return slices.ContainsFunc(userIDs, room.IsMember)
+	}), nil
+}
+
+// listWhere は条件に合う部屋だけを複製して、ID順に返す
+func (r *InmemRoomRepository) listWhere(match func(room *model.Room) bool) []*model.Room {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	var rooms []*model.Room
+	for _, original := range r.data {
+		if !match(original) {
+			continue
+		}
+		if safeCopy := r.jsonDeepCopy(original); safeCopy != nil {
+			rooms = append(rooms, safeCopy)
+		}
+	}
+
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].ID < rooms[j].ID
+	})
+	return rooms
+}
+
 func (r *InmemRoomRepository) GetRoomByID(ctx context.Context, id int64) (*model.Room, error) {
 	r.mtx.RLock()
 	defer r.mtx.RUnlock()
@@ -82,7 +139,12 @@ func (r *InmemRoomRepository) GetRoomByID(ctx context.Context, id int64) (*model
 	if !ok {
 		return nil, repository.ErrEntityNotFound
 	}
-	return room, nil
+
+	safeCopy := r.jsonDeepCopy(room)
+	if safeCopy == nil {
+		return nil, errors.New("failed to copy room")
+	}
+	return safeCopy, nil
 }
 
 func (r *InmemRoomRepository) CleanupRooms(expiration time.Duration) {
@@ -93,11 +155,7 @@ func (r *InmemRoomRepository) CleanupRooms(expiration time.Duration) {
 	deletedCount := 0
 
 	for id, room := range r.data {
-		room.Mu.Lock()
-		updatedAt := room.UpdatedAt
-		room.Mu.Unlock()
-
-		if updatedAt.Before(threshold) {
+		if room.UpdatedAt.Before(threshold) {
 			delete(r.data, id)
 			deletedCount++
 		}
@@ -110,9 +168,6 @@ func (r *InmemRoomRepository) CleanupRooms(expiration time.Duration) {
 
 // JSONを使った簡易DeepCopy
 func (r *InmemRoomRepository) jsonDeepCopy(src *model.Room) *model.Room {
-	src.Mu.Lock()
-	defer src.Mu.Unlock()
-
 	b, err := json.Marshal(src)
 	if err != nil {
 		return nil
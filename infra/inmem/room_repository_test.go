@@ -0,0 +1,66 @@
+package inmem
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/model"
+)
+
+func TestListDueRooms(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	repo := NewInmemRoomRepository()
+
+	newRoom := func(name string, members []int64, setup func(g *game.Game)) int64 {
+		t.Helper()
+		room := &model.Room{Name: name, MemberIDs: members}
+		if setup != nil {
+			room.Game = game.NewGameWithSeed(members, game.RuleSet{}, game.Seed{})
+			setup(room.Game)
+		}
+		if err := repo.SaveRoom(ctx, room); err != nil {
+			t.Fatal(err)
+		}
+		return room.ID
+	}
+
+	idle := newRoom("待機中", []int64{1, 2}, nil)
+	noDeadline := newRoom("期限なし", []int64{3, 4}, func(g *game.Game) {})
+	due := newRoom("期限切れ", []int64{5, 6}, func(g *game.Game) { g.TurnDeadline = now.Add(-time.Second) })
+	notYet := newRoom("期限前", []int64{7, 8}, func(g *game.Game) { g.TurnDeadline = now.Add(time.Minute) })
+	finished := newRoom("終了", []int64{9, 10}, func(g *game.Game) {
+		g.TurnDeadline = now.Add(-time.Second)
+		g.IsFinished = true
+	})
+
+	tests := []struct {
+		name    string
+		userIDs []int64
+		want    []int64
+	}{
+		{"期限切れだけ", nil, []int64{due}},
+		// 待機中・終了した部屋は、切断したメンバーがいても読まない
+		{"切断したメンバーのいる進行中の部屋", []int64{1, 3, 9}, []int64{noDeadline, due}},
+		{"期限前の部屋の切断", []int64{8}, []int64{due, notYet}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rooms, err := repo.ListDueRooms(ctx, now, tt.userIDs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]int64, len(rooms))
+			for i, room := range rooms {
+				got[i] = room.ID
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("部屋 = %v, want %v (待機中 %d, 終了 %d)", got, tt.want, idle, finished)
+			}
+		})
+	}
+}
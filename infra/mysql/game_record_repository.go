@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+var _ repository.GameRecordRepository = &MySQLGameRecordRepository{}
+
+type MySQLGameRecordRepository struct {
+	db *sql.DB
+}
+
+func NewMySQLGameRecordRepository(db *sql.DB) *MySQLGameRecordRepository {
+	return &MySQLGameRecordRepository{db: db}
+}
+
+// SaveGameRecord はゲームの記録を新規作成する（記録は変更しない）
+func (r *MySQLGameRecordRepository) SaveGameRecord(ctx context.Context, record *model.GameRecord) error {
+	if record.ID != 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(record.Record)
+	if err != nil {
+		return fmt.Errorf("failed to encode game record: %w", err)
+	}
+
+	query := `
+		INSERT INTO game_records (room_id, record, finished_at)
+		VALUES (?, ?, ?)
+	`
+	res, err := r.db.ExecContext(ctx, query, record.RoomID, data, record.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert game record: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	record.ID = id
+	return nil
+}
+
+// SaveGameRecordOnce は終了したゲームの記録を、部屋とゲーム（SeedHash）ごとに1つだけ作成する
+// 既にあれば挿入せず、その行のIDを LAST_INSERT_ID で受け取る
+func (r *MySQLGameRecordRepository) SaveGameRecordOnce(ctx context.Context, record *model.GameRecord) error {
+	if record.ID != 0 {
+		return nil
+	}
+	if record.Record == nil || record.Record.SeedHash == "" {
+		return r.SaveGameRecord(ctx, record)
+	}
+
+	data, err := json.Marshal(record.Record)
+	if err != nil {
+		return fmt.Errorf("failed to encode game record: %w", err)
+	}
+
+	query := `
+		INSERT INTO game_records (room_id, seed_hash, record, finished_at)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)
+	`
+	res, err := r.db.ExecContext(ctx, query, record.RoomID, record.Record.SeedHash, data, record.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert game record: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	record.ID = id
+	return nil
+}
+
+// GetGameRecordByID はIDでゲームの記録を取得する
+func (r *MySQLGameRecordRepository) GetGameRecordByID(ctx context.Context, id int64) (*model.GameRecord, error) {
+	query := `
+		SELECT id, room_id, record, finished_at
+		FROM game_records WHERE id = ?
+	`
+	row := r.db.QueryRowContext(ctx, query, id)
+
+	var record model.GameRecord
+	var data []byte
+	if err := row.Scan(&record.ID, &record.RoomID, &data, &record.FinishedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrEntityNotFound
+		}
+		return nil, fmt.Errorf("failed to scan game record: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &record.Record); err != nil {
+		return nil, fmt.Errorf("failed to decode game record: %w", err)
+	}
+	return &record, nil
+}
@@ -0,0 +1,390 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+// gameStateVersion は game_state 列に保存するゲームの形式のバージョン
+// game.Game の保存形式に互換性のない変更をしたら上げる
+const gameStateVersion = 1
+
+const (
+	roleMember    = "member"
+	roleSpectator = "spectator"
+)
+
+var _ repository.RoomRepository = &MySQLRoomRepository{}
+
+// MySQLRoomRepository は部屋とゲームの状態を MySQL に保存する
+// 再起動後も続きから再開でき、複数のサーバーで同じ部屋を扱える
+// 同時に更新された場合は version 列で検出する
+type MySQLRoomRepository struct {
+	db *sql.DB
+}
+
+func NewMySQLRoomRepository(db *sql.DB) *MySQLRoomRepository {
+	return &MySQLRoomRepository{db: db}
+}
+
+// SaveRoom は部屋を新規作成または更新する
+func (r *MySQLRoomRepository) SaveRoom(ctx context.Context, room *model.Room) error {
+	now := time.Now()
+	if room.CreatedAt.IsZero() {
+		room.CreatedAt = now
+	}
+	room.UpdatedAt = now
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	creating := room.ID == 0
+	if creating {
+		err = r.create(ctx, tx, room)
+	} else {
+		err = r.update(ctx, tx, room)
+	}
+	if err == nil {
+		err = r.saveMembers(ctx, tx, room)
+	}
+	if err == nil {
+		if err = tx.Commit(); err != nil {
+			err = fmt.Errorf("failed to commit room: %w", err)
+		}
+	}
+	if err != nil {
+		// 作成に失敗した場合は割り当てたIDを戻す
+		if creating {
+			room.ID = 0
+		}
+		return err
+	}
+
+	room.Version++
+	return nil
+}
+
+// UpdateRoom は部屋情報を更新する
+func (r *MySQLRoomRepository) UpdateRoom(ctx context.Context, room *model.Room) error {
+	return r.SaveRoom(ctx, room)
+}
+
+func (r *MySQLRoomRepository) create(ctx context.Context, tx *sql.Tx, room *model.Room) error {
+	cols, err := encodeRoom(room)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO rooms (name, owner_id, bot_ids, prev_ranks, rules,
+			spectator_full_view, spectator_delay, game_state, game_state_version, playing, next_deadline, version, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	res, err := tx.ExecContext(ctx, query,
+		room.Name, room.OwnerID, cols.botIDs, cols.prevRanks, cols.rules,
+		room.SpectatorFullView, room.SpectatorDelay, cols.gameState, gameStateVersion, room.IsPlaying(), cols.nextDeadline,
+		room.Version+1, room.CreatedAt, room.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert room: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	room.ID = id
+	return nil
+}
+
+func (r *MySQLRoomRepository) update(ctx context.Context, tx *sql.Tx, room *model.Room) error {
+	cols, err := encodeRoom(room)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE rooms
+		SET name = ?, owner_id = ?, bot_ids = ?, prev_ranks = ?, rules = ?,
+			spectator_full_view = ?, spectator_delay = ?, game_state = ?, game_state_version = ?,
+			playing = ?, next_deadline = ?, version = ?, updated_at = ?
+		WHERE id = ? AND version = ?
+	`
+	res, err := tx.ExecContext(ctx, query,
+		room.Name, room.OwnerID, cols.botIDs, cols.prevRanks, cols.rules,
+		room.SpectatorFullView, room.SpectatorDelay, cols.gameState, gameStateVersion,
+		room.IsPlaying(), cols.nextDeadline, room.Version+1, room.UpdatedAt,
+		room.ID, room.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update room: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	// 更新されなかった場合は、削除済みか読み込んだ後に他の更新があったか
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM rooms WHERE id = ?)`, room.ID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check room: %w", err)
+	}
+	if !exists {
+		return repository.ErrEntityNotFound
+	}
+	return repository.ErrConflict
+}
+
+// saveMembers はメンバーと観戦者を入れ替える
+func (r *MySQLRoomRepository) saveMembers(ctx context.Context, tx *sql.Tx, room *model.Room) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM room_members WHERE room_id = ?`, room.ID); err != nil {
+		return fmt.Errorf("failed to delete room members: %w", err)
+	}
+
+	query := `
+		INSERT INTO room_members (room_id, user_id, role, position)
+		VALUES (?, ?, ?, ?)
+	`
+	for i, uid := range room.MemberIDs {
+		if _, err := tx.ExecContext(ctx, query, room.ID, uid, roleMember, i); err != nil {
+			return fmt.Errorf("failed to insert room member: %w", err)
+		}
+	}
+	for i, uid := range room.SpectatorIDs {
+		if _, err := tx.ExecContext(ctx, query, room.ID, uid, roleSpectator, i); err != nil {
+			return fmt.Errorf("failed to insert room spectator: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteRoom は部屋を削除する（メンバーは外部キーで一緒に削除される）
+func (r *MySQLRoomRepository) DeleteRoom(ctx context.Context, id int64) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM rooms WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete room: %w", err)
+	}
+	return nil
+}
+
+// ListRooms は部屋一覧を取得する
+func (r *MySQLRoomRepository) ListRooms(ctx context.Context) ([]*model.Room, error) {
+	return r.query(ctx, `ORDER BY r.id`)
+}
+
+// ListRoomsByUserID はユーザーがメンバーか観戦者になっている部屋を取得する
+func (r *MySQLRoomRepository) ListRoomsByUserID(ctx context.Context, userID int64) ([]*model.Room, error) {
+	return r.query(ctx, `WHERE r.id IN (SELECT room_id FROM room_members WHERE user_id = ?) ORDER BY r.id`, userID)
+}
+
+// ListDueRooms は期限が来た部屋と、userIDs がメンバーになっている進行中の部屋を取得する
+// next_deadline は進行中のゲームにしか設定しないため、期限の条件だけで進行中の部屋に絞れる
+func (r *MySQLRoomRepository) ListDueRooms(ctx context.Context, now time.Time, userIDs []int64) ([]*model.Room, error) {
+	where := `WHERE r.next_deadline <= ?`
+	args := []any{now}
+	if len(userIDs) > 0 {
+		placeholders := make([]string, len(userIDs))
+		for i, uid := range userIDs {
+			placeholders[i] = "?"
+			args = append(args, uid)
+		}
+		where += ` OR (r.playing AND r.id IN (
+			SELECT room_id FROM room_members WHERE role = '` + roleMember + `' AND user_id IN (` + strings.Join(placeholders, ", ") + `)
+		))`
+	}
+	return r.query(ctx, where+` ORDER BY r.id`, args...)
+}
+
+// GetRoomByID はIDから部屋を取得する
+func (r *MySQLRoomRepository) GetRoomByID(ctx context.Context, id int64) (*model.Room, error) {
+	rooms, err := r.query(ctx, `WHERE r.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rooms) == 0 {
+		return nil, repository.ErrEntityNotFound
+	}
+	return rooms[0], nil
+}
+
+// CleanupRooms は、expiration の間更新されていない部屋を削除する
+func (r *MySQLRoomRepository) CleanupRooms(expiration time.Duration) {
+	threshold := time.Now().Add(-expiration)
+
+	res, err := r.db.Exec(`DELETE FROM rooms WHERE updated_at < ?`, threshold)
+	if err != nil {
+		fmt.Printf("Failed to clean up rooms: %v\n", err)
+		return
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		fmt.Printf("Cleaned up %d rooms\n", n)
+	}
+}
+
+// query は条件に合う部屋を、メンバーと観戦者を含めて読み込む
+func (r *MySQLRoomRepository) query(ctx context.Context, where string, args ...any) ([]*model.Room, error) {
+	query := `
+		SELECT r.id, r.name, r.owner_id, r.bot_ids, r.prev_ranks, r.rules,
+			r.spectator_full_view, r.spectator_delay, r.game_state, r.game_state_version, r.version, r.created_at, r.updated_at
+		FROM rooms r
+	` + where
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rooms: %w", err)
+	}
+	defer rows.Close()
+
+	var rooms []*model.Room
+	byID := make(map[int64]*model.Room)
+	for rows.Next() {
+		var room model.Room
+		var cols roomColumns
+		var stateVersion int
+		if err := rows.Scan(
+			&room.ID, &room.Name, &room.OwnerID, &cols.botIDs, &cols.prevRanks, &cols.rules,
+			&room.SpectatorFullView, &room.SpectatorDelay, &cols.gameState, &stateVersion, &room.Version, &room.CreatedAt, &room.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan room: %w", err)
+		}
+		if err := decodeRoom(&room, cols, stateVersion); err != nil {
+			return nil, fmt.Errorf("room %d: %w", room.ID, err)
+		}
+		rooms = append(rooms, &room)
+		byID[room.ID] = &room
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rooms: %w", err)
+	}
+	if len(rooms) == 0 {
+		return rooms, nil
+	}
+
+	if err := r.queryMembers(ctx, byID); err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
+
+// queryMembers は部屋のメンバーと観戦者を参加した順に読み込む
+func (r *MySQLRoomRepository) queryMembers(ctx context.Context, byID map[int64]*model.Room) error {
+	placeholders := make([]string, 0, len(byID))
+	args := make([]any, 0, len(byID))
+	for id := range byID {
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+
+	query := `
+		SELECT room_id, user_id, role
+		FROM room_members
+		WHERE room_id IN (` + strings.Join(placeholders, ", ") + `)
+		ORDER BY room_id, position
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query room members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var roomID, userID int64
+		var role string
+		if err := rows.Scan(&roomID, &userID, &role); err != nil {
+			return fmt.Errorf("failed to scan room member: %w", err)
+		}
+
+		room, ok := byID[roomID]
+		if !ok {
+			continue
+		}
+		switch role {
+		case roleMember:
+			room.MemberIDs = append(room.MemberIDs, userID)
+		case roleSpectator:
+			room.SpectatorIDs = append(room.SpectatorIDs, userID)
+		}
+	}
+	return rows.Err()
+}
+
+// roomColumns は JSON などに変換して保存する列
+type roomColumns struct {
+	botIDs    []byte
+	prevRanks []byte
+	rules     []byte
+	gameState []byte
+	// 次に自動で進める期限（進行中のゲームで期限があるときだけ設定する）
+	nextDeadline sql.NullTime
+}
+
+func encodeRoom(room *model.Room) (roomColumns, error) {
+	var cols roomColumns
+	var err error
+
+	botIDs := room.BotIDs
+	if botIDs == nil {
+		botIDs = []int64{}
+	}
+	if cols.botIDs, err = json.Marshal(botIDs); err != nil {
+		return cols, fmt.Errorf("failed to encode bot ids: %w", err)
+	}
+	prevRanks := room.PrevRanks
+	if prevRanks == nil {
+		prevRanks = map[int64]int{}
+	}
+	if cols.prevRanks, err = json.Marshal(prevRanks); err != nil {
+		return cols, fmt.Errorf("failed to encode prev ranks: %w", err)
+	}
+	if cols.rules, err = json.Marshal(room.Rules); err != nil {
+		return cols, fmt.Errorf("failed to encode rules: %w", err)
+	}
+	if room.Game != nil {
+		if cols.gameState, err = json.Marshal(room.Game); err != nil {
+			return cols, fmt.Errorf("failed to encode game state: %w", err)
+		}
+	}
+	if deadline := room.NextDeadline(); !deadline.IsZero() {
+		cols.nextDeadline = sql.NullTime{Time: deadline, Valid: true}
+	}
+	return cols, nil
+}
+
+func decodeRoom(room *model.Room, cols roomColumns, version int) error {
+	if err := json.Unmarshal(cols.botIDs, &room.BotIDs); err != nil {
+		return fmt.Errorf("failed to decode bot ids: %w", err)
+	}
+	if err := json.Unmarshal(cols.prevRanks, &room.PrevRanks); err != nil {
+		return fmt.Errorf("failed to decode prev ranks: %w", err)
+	}
+	if err := json.Unmarshal(cols.rules, &room.Rules); err != nil {
+		return fmt.Errorf("failed to decode rules: %w", err)
+	}
+
+	if cols.gameState == nil {
+		return nil
+	}
+	if version != gameStateVersion {
+		return fmt.Errorf("unsupported game state version %d", version)
+	}
+	var g game.Game
+	if err := json.Unmarshal(cols.gameState, &g); err != nil {
+		return fmt.Errorf("failed to decode game state: %w", err)
+	}
+	room.Game = &g
+	return nil
+}
@@ -0,0 +1,194 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/repository"
+	usecase "github.com/ne241099/daifugo-server/usecase/game"
+)
+
+// 1回の起動で行動する最大回数（無限ループ防止）
+const maxActionsPerRun = 200
+
+// 続けて失敗した場合に諦めるまでの回数（保存の失敗などは部屋を読み直してやり直す）
+const maxStepErrors = 3
+
+type actionKind int
+
+const (
+	actionPlay actionKind = iota
+	actionPass
+	actionResolveEffect
+	actionExchange
+)
+
+type action struct {
+	kind    actionKind
+	userID  int64
+	cardIDs []int
+}
+
+// Driver はボットの手番が来たら、人間と同じユースケースを通して行動させる
+type Driver struct {
+	Strategy             Strategy
+	RoomRepository       repository.RoomRepository
+	PlayCardUseCase      usecase.PlayCardUseCase
+	PassUseCase          usecase.PassUseCase
+	ResolveEffectUseCase usecase.ResolveEffectUseCase
+	ExchangeCardsUseCase usecase.ExchangeCardsUseCase
+	// Notify は行動の後に呼ばれ、SSE への配信などを行う
+	Notify func(roomID int64)
+	// Delay は1手ごとの待ち時間
+	Delay time.Duration
+
+	mu      sync.Mutex
+	running map[int64]bool
+	again   map[int64]bool
+}
+
+// Trigger は部屋の状態を確認し、ボットが行動すべきなら非同期で行動させる
+func (d *Driver) Trigger(roomID int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.running == nil {
+		d.running = make(map[int64]bool)
+		d.again = make(map[int64]bool)
+	}
+
+	// 実行中なら、終わった後にもう一度確認させる
+	if d.running[roomID] {
+		d.again[roomID] = true
+		return
+	}
+	d.running[roomID] = true
+
+	go d.run(roomID)
+}
+
+func (d *Driver) run(roomID int64) {
+	for {
+		d.actAll(roomID)
+
+		d.mu.Lock()
+		if !d.again[roomID] {
+			delete(d.running, roomID)
+			d.mu.Unlock()
+			return
+		}
+		delete(d.again, roomID)
+		d.mu.Unlock()
+	}
+}
+
+func (d *Driver) actAll(roomID int64) {
+	ctx := context.Background()
+	errCount := 0
+	for i := 0; i < maxActionsPerRun; i++ {
+		time.Sleep(d.Delay)
+
+		acted, err := d.step(ctx, roomID)
+		if err != nil {
+			log.Printf("bot: room %d: %v", roomID, err)
+			if errCount++; errCount >= maxStepErrors {
+				return
+			}
+			continue
+		}
+		errCount = 0
+		if !acted {
+			return
+		}
+	}
+}
+
+// step はボットの行動を1つ実行する。行動しなかった場合は false を返す
+func (d *Driver) step(ctx context.Context, roomID int64) (bool, error) {
+	room, err := d.RoomRepository.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return false, nil // 部屋が削除された
+	}
+
+	act := d.decide(room.Game)
+
+	if act == nil {
+		return false, nil
+	}
+
+	switch act.kind {
+	case actionPlay:
+		_, err = d.PlayCardUseCase.Execute(ctx, roomID, act.userID, act.cardIDs)
+		// ルール上出せなかった場合はパスする
+		// 保存の失敗などはパスせず、部屋を読み直してからやり直す
+		if errors.Is(err, usecase.ErrInvalidMove) {
+			_, err = d.PassUseCase.Execute(ctx, roomID, act.userID)
+		}
+	case actionPass:
+		_, err = d.PassUseCase.Execute(ctx, roomID, act.userID)
+	case actionResolveEffect:
+		_, err = d.ResolveEffectUseCase.Execute(ctx, roomID, act.userID, act.cardIDs)
+	case actionExchange:
+		_, err = d.ExchangeCardsUseCase.Execute(ctx, roomID, act.userID, act.cardIDs)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if d.Notify != nil {
+		d.Notify(roomID)
+	}
+
+	return true, nil
+}
+
+// decide は現在の状態からボットの次の行動を決める
+// 代打ち中の人間の席も、ボットの席と同じように行動させる
+func (d *Driver) decide(g *game.Game) *action {
+	if g == nil || g.IsFinished || len(g.Players) == 0 {
+		return nil
+	}
+
+	// カード交換
+	for _, ex := range g.PendingExchanges {
+		if g.IsBotControlled(ex.FromID) {
+			cards := d.Strategy.ChooseCards(g, ex.FromID, ex.Count)
+			return &action{kind: actionExchange, userID: ex.FromID, cardIDs: cardIDs(cards)}
+		}
+	}
+	if g.IsExchanging() {
+		return nil
+	}
+
+	// 7渡し・10捨て
+	if effect := g.PendingEffect; effect != nil {
+		if !g.IsBotControlled(effect.UserID) {
+			return nil
+		}
+		cards := d.Strategy.ChooseCards(g, effect.UserID, effect.Count)
+		return &action{kind: actionResolveEffect, userID: effect.UserID, cardIDs: cardIDs(cards)}
+	}
+
+	// 手番
+	player := g.Players[g.Turn]
+	if !g.IsBotControlled(player.UserID) || len(player.Hand) == 0 {
+		return nil
+	}
+	cards := d.Strategy.ChooseMove(g, player.UserID)
+	if cards == nil {
+		return &action{kind: actionPass, userID: player.UserID}
+	}
+	return &action{kind: actionPlay, userID: player.UserID, cardIDs: cardIDs(cards)}
+}
+
+func cardIDs(cards []*game.Card) []int {
+	ids := make([]int, len(cards))
+	for i, c := range cards {
+		ids[i] = c.ID
+	}
+	return ids
+}
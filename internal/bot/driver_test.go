@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ne241099/daifugo-server/infra/inmem"
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+	usecase "github.com/ne241099/daifugo-server/usecase/game"
+)
+
+// stubPlayCard は指定したエラーを返す
+type stubPlayCard struct {
+	err error
+}
+
+func (s *stubPlayCard) Execute(ctx context.Context, roomID int64, userID int64, cardIDs []int) (*model.Room, error) {
+	return nil, s.err
+}
+
+// stubPass は呼ばれた回数を数える
+type stubPass struct {
+	calls int
+}
+
+func (s *stubPass) Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
+	s.calls++
+	return nil, nil
+}
+
+func TestStepPassesOnlyOnInvalidMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		playErr  error
+		wantPass bool
+		wantErr  error
+	}{
+		{"ルール上出せない", fmt.Errorf("出せません: %w", usecase.ErrInvalidMove), true, nil},
+		{"保存が競合し続けた", repository.ErrConflict, false, repository.ErrConflict},
+		{"部屋が読めない", repository.ErrEntityNotFound, false, repository.ErrEntityNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rooms := inmem.NewInmemRoomRepository()
+			room := &model.Room{
+				Name:   "test",
+				BotIDs: []int64{-1, -2},
+				Game:   game.NewGameWithSeed([]int64{-1, -2}, game.RuleSet{}, game.Seed{}),
+			}
+			if err := rooms.SaveRoom(ctx, room); err != nil {
+				t.Fatal(err)
+			}
+
+			pass := &stubPass{}
+			d := &Driver{
+				Strategy:        NewGreedyStrategy(),
+				RoomRepository:  rooms,
+				PlayCardUseCase: &stubPlayCard{err: tt.playErr},
+				PassUseCase:     pass,
+			}
+
+			acted, err := d.step(ctx, room.ID)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if acted != tt.wantPass {
+				t.Fatalf("acted = %v, want %v", acted, tt.wantPass)
+			}
+			if got := pass.calls > 0; got != tt.wantPass {
+				t.Fatalf("パスした = %v, want %v", got, tt.wantPass)
+			}
+		})
+	}
+}
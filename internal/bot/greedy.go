@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"sort"
+
+	"github.com/ne241099/daifugo-server/internal/game"
+)
+
+var _ Strategy = &GreedyStrategy{}
+
+// GreedyStrategy は出せる手のうち、枚数が多く弱い手から出す単純な戦略
+type GreedyStrategy struct{}
+
+func NewGreedyStrategy() *GreedyStrategy {
+	return &GreedyStrategy{}
+}
+
+func (s *GreedyStrategy) ChooseMove(g *game.Game, userID int64) []*game.Card {
+	moves := g.LegalMoves(userID)
+	if len(moves) == 0 {
+		return nil
+	}
+
+	isRev := g.EffectiveRevolution()
+	sort.SliceStable(moves, func(i, j int) bool {
+		// 枚数が多い手を優先
+		if len(moves[i]) != len(moves[j]) {
+			return len(moves[i]) > len(moves[j])
+		}
+		// ジョーカーはなるべく温存
+		if ji, jj := countJokers(moves[i]), countJokers(moves[j]); ji != jj {
+			return ji < jj
+		}
+		// 弱い手から出す
+		return moveStrength(moves[i], isRev) < moveStrength(moves[j], isRev)
+	})
+	return moves[0]
+}
+
+func (s *GreedyStrategy) ChooseCards(g *game.Game, userID int64, count int) []*game.Card {
+	var hand []*game.Card
+	for _, p := range g.Players {
+		if p.UserID == userID {
+			hand = append(hand, p.Hand...)
+			break
+		}
+	}
+	if count > len(hand) {
+		count = len(hand)
+	}
+
+	// 弱いカードから手放す
+	isRev := g.EffectiveRevolution()
+	sort.SliceStable(hand, func(i, j int) bool {
+		return game.GetStrength(hand[i], isRev) < game.GetStrength(hand[j], isRev)
+	})
+	return hand[:count]
+}
+
+func countJokers(cards []*game.Card) int {
+	n := 0
+	for _, c := range cards {
+		if c.Suit == game.SuitJoker {
+			n++
+		}
+	}
+	return n
+}
+
+func moveStrength(cards []*game.Card, isRev bool) int {
+	_, strength, err := game.AnalyzeHand(cards, isRev)
+	if err != nil {
+		return 0
+	}
+	return strength
+}
@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/ne241099/daifugo-server/internal/game"
+)
+
+// findCards は手札からIDでカードを探す
+func findCards(t *testing.T, g *game.Game, userID int64, ids []int) []*game.Card {
+	t.Helper()
+	for _, p := range g.Players {
+		if p.UserID != userID {
+			continue
+		}
+		cards := make([]*game.Card, 0, len(ids))
+		for _, id := range ids {
+			i := slices.IndexFunc(p.Hand, func(c *game.Card) bool { return c.ID == id })
+			if i < 0 {
+				t.Fatalf("ボット %d が持っていないカード %d を選んだ", userID, id)
+			}
+			cards = append(cards, p.Hand[i])
+		}
+		return cards
+	}
+	t.Fatalf("ボット %d がいない", userID)
+	return nil
+}
+
+// playOut はボットだけでゲームを最後まで進め、選んだ手がすべて正しいか確認する
+func playOut(t *testing.T, d *Driver, g *game.Game) {
+	t.Helper()
+	for step := 0; !g.IsFinished; step++ {
+		if step > 1000 {
+			t.Fatal("ゲームが終わらない")
+		}
+		act := d.decide(g)
+		if act == nil {
+			t.Fatalf("ボットが行動しない（%s）", g.Phase())
+		}
+
+		var err error
+		switch act.kind {
+		case actionPlay:
+			cards := findCards(t, g, act.userID, act.cardIDs)
+			key := fmt.Sprint(act.cardIDs)
+			legal := slices.ContainsFunc(g.LegalMoves(act.userID), func(m []*game.Card) bool {
+				return fmt.Sprint(cardIDs(m)) == key
+			})
+			if !legal {
+				t.Fatalf("出せる手に含まれない %v を選んだ", cards)
+			}
+			err = g.Play(act.userID, cards)
+		case actionPass:
+			if moves := g.LegalMoves(act.userID); len(g.FieldCards) == 0 || len(moves) > 0 {
+				t.Fatalf("出せる手 %v があるのにパスした", moves)
+			}
+			err = g.Pass(act.userID)
+		case actionResolveEffect:
+			err = g.ResolveEffect(act.userID, findCards(t, g, act.userID, act.cardIDs))
+		case actionExchange:
+			err = g.SubmitExchange(act.userID, findCards(t, g, act.userID, act.cardIDs))
+		}
+		if err != nil {
+			t.Fatalf("ボットの行動が反則になった: %v", err)
+		}
+	}
+}
+
+func TestGreedyStrategyPlaysOnlyLegalMoves(t *testing.T) {
+	rules := []game.RuleSet{
+		game.DefaultRuleSet(),
+		{
+			EightCut: true, ElevenBack: true, SpadeThree: true, Revolution: true, MiyakoOchi: true,
+			Shibari: true, SevenPass: true, TenDiscard: true, NineReverse: true, FiveSkip: true,
+			ForbiddenFinish: true, JokerCount: 2,
+		},
+		{JokerCount: 0},
+	}
+	d := &Driver{Strategy: NewGreedyStrategy()}
+
+	for i, r := range rules {
+		for n := 2; n <= 5; n++ {
+			t.Run(fmt.Sprintf("rules%d/%dplayers", i, n), func(t *testing.T) {
+				ids := make([]int64, n)
+				for j := range ids {
+					ids[j] = int64(-j - 1)
+				}
+				for s := byte(0); s < 10; s++ {
+					seed := game.Seed{byte(i), byte(n), s}
+					g := game.NewGameWithSeed(ids, r, seed)
+					playOut(t, d, g)
+
+					// 前回の順位でカード交換をしてからもう1ゲーム
+					seed[31] = 1
+					g.ResetWithSeed(r, seed)
+					playOut(t, d, g)
+				}
+			})
+		}
+	}
+}
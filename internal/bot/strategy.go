@@ -0,0 +1,11 @@
+package bot
+
+import "github.com/ne241099/daifugo-server/internal/game"
+
+// Strategy はボットの思考ルーチン
+type Strategy interface {
+	// ChooseMove は出すカードを返す（nil ならパス）
+	ChooseMove(g *game.Game, userID int64) []*game.Card
+	// ChooseCards はカード交換や7渡し・10捨てで手放すカードを count 枚返す
+	ChooseCards(g *game.Game, userID int64, count int) []*game.Card
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
 type Config struct {
@@ -14,6 +15,21 @@ type Config struct {
 	DBHost        string
 	DBPort        string
 	DBName        string
+
+	// 部屋の保存先（"memory": メモリ, "mysql": MySQL。MySQL は rooms テーブルを作成してから指定する）
+	RoomStore string
+
+	// カード交換の制限時間
+	ExchangeTimeout time.Duration
+	// ボットが1手ごとに待つ時間
+	BotDelay time.Duration
+
+	// 接続が切れてからオフラインとみなすまでの猶予
+	PresenceGrace time.Duration
+	// オフラインのプレイヤーの入力待ちを代わりに進めるまでの時間（0なら進めない）
+	DisconnectTimeout time.Duration
+	// 切断したプレイヤーへの対応（"pass": パスする, "bot": ボットが代打ちする）
+	DisconnectAction string
 }
 
 // Load は環境変数から設定を読み込む
@@ -28,6 +44,15 @@ func Load() *Config {
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBPort:     getEnv("DB_PORT", "3306"),
 		DBName:     getEnv("DB_NAME", "daifugo_db"),
+
+		RoomStore: getEnv("ROOM_STORE", "memory"),
+
+		ExchangeTimeout: getDurationEnv("EXCHANGE_TIMEOUT", 30*time.Second),
+		BotDelay:        getDurationEnv("BOT_DELAY", 1*time.Second),
+
+		PresenceGrace:     getDurationEnv("PRESENCE_GRACE", 10*time.Second),
+		DisconnectTimeout: getDurationEnv("DISCONNECT_TIMEOUT", 60*time.Second),
+		DisconnectAction:  getEnv("DISCONNECT_ACTION", "pass"),
 	}
 }
 
@@ -38,6 +63,15 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getDurationEnv(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
 func (c *Config) DSN() string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
 		c.DBUser, c.DBPassword, c.DBHost, c.DBPort, c.DBName,
@@ -35,7 +35,11 @@ const (
 	RankAce   = 1
 	RankTwo   = 2
 	RankThree = 3
+	RankFive  = 5
+	RankSeven = 7
 	RankEight = 8
+	RankNine  = 9
+	RankTen   = 10
 	RankJack  = 11
 	RankQueen = 12
 	RankKing  = 13
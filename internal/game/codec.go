@@ -0,0 +1,32 @@
+package game
+
+import "encoding/json"
+
+// UnmarshalJSON は保存したゲームを復元する
+// 順位の確定などで同じプレイヤーを書き換えるため、
+// あがった人・反則あがり・都落ちの一覧は Players の要素を指すように戻す
+func (g *Game) UnmarshalJSON(b []byte) error {
+	type plain Game
+	if err := json.Unmarshal(b, (*plain)(g)); err != nil {
+		return err
+	}
+
+	g.FinishedPlayers = g.linkPlayers(g.FinishedPlayers)
+	g.ForbiddenFinishers = g.linkPlayers(g.ForbiddenFinishers)
+	if g.MiyakoOchiPlayer != nil {
+		if idx := g.playerIndex(g.MiyakoOchiPlayer.UserID); idx >= 0 {
+			g.MiyakoOchiPlayer = g.Players[idx]
+		}
+	}
+	return nil
+}
+
+// linkPlayers は同じユーザーの Players の要素に置き換える
+func (g *Game) linkPlayers(players []*Player) []*Player {
+	for i, p := range players {
+		if idx := g.playerIndex(p.UserID); idx >= 0 {
+			players[i] = g.Players[idx]
+		}
+	}
+	return players
+}
@@ -1,7 +1,7 @@
 package game
 
 import (
-	"math/rand"
+	"math/rand/v2"
 )
 
 type Deck []*Card
@@ -24,8 +24,15 @@ func NewDeck(jokerCount int) Deck {
 	return d
 }
 
+// Shuffle は新しい種でシャッフルする
 func (d Deck) Shuffle() {
-	rand.Shuffle(len(d), func(i, j int) {
+	d.ShuffleWithSeed(NewSeed())
+}
+
+// ShuffleWithSeed は種から決まる順序でシャッフルする（同じ種なら同じ並びになる）
+func (d Deck) ShuffleWithSeed(seed Seed) {
+	r := rand.New(rand.NewChaCha8(seed))
+	r.Shuffle(len(d), func(i, j int) {
 		d[i], d[j] = d[j], d[i]
 	})
 }
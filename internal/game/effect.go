@@ -0,0 +1,112 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+type EffectType int
+
+const (
+	EffectNone       EffectType = iota
+	EffectSevenPass             // 7渡し
+	EffectTenDiscard            // 10捨て
+)
+
+func (e EffectType) String() string {
+	switch e {
+	case EffectSevenPass:
+		return "seven_pass"
+	case EffectTenDiscard:
+		return "ten_discard"
+	default:
+		return "none"
+	}
+}
+
+// PendingEffect はプレイヤーのカード選択を待っている効果
+type PendingEffect struct {
+	Type   EffectType `json:"type"`
+	UserID int64      `json:"user_id"`
+	// 選択する枚数
+	Count int `json:"count"`
+	// 選択後に場を流すか（8切りと同時に出した場合）
+	ClearTable bool `json:"clear_table"`
+	// 選択後に手札がなくなった場合の反則あがりの理由
+	ForbiddenReason string `json:"forbidden_reason"`
+}
+
+// detectEffect は出したカードから選択が必要な効果を判定する
+// 7と10を同時に出した場合は7渡しを優先する
+func (g *Game) detectEffect(player *Player, cards []*Card) *PendingEffect {
+	// あがった場合は効果なし
+	if len(player.Hand) == 0 {
+		return nil
+	}
+
+	sevens := countRank(cards, RankSeven)
+	tens := countRank(cards, RankTen)
+
+	effect := &PendingEffect{UserID: player.UserID}
+	switch {
+	case g.Rules.SevenPass && sevens > 0:
+		effect.Type = EffectSevenPass
+		effect.Count = sevens
+	case g.Rules.TenDiscard && tens > 0:
+		effect.Type = EffectTenDiscard
+		effect.Count = tens
+	default:
+		return nil
+	}
+
+	// 手札が足りない場合は持っている分だけ
+	if effect.Count > len(player.Hand) {
+		effect.Count = len(player.Hand)
+	}
+	return effect
+}
+
+// ResolveEffect は選択待ちの効果に対してカードを選んで解決する
+func (g *Game) ResolveEffect(userID int64, cards []*Card) error {
+	effect := g.PendingEffect
+	if effect == nil {
+		return errors.New("選択待ちの効果はありません")
+	}
+	if effect.UserID != userID {
+		return errors.New("あなたの選択ではありません")
+	}
+
+	player := g.Players[g.Turn]
+	if player.UserID != userID {
+		return errors.New("あなたのターンではありません")
+	}
+
+	if hasDuplicateCards(cards) {
+		return errors.New("同じカードが重複しています")
+	}
+	if len(cards) != effect.Count {
+		return fmt.Errorf("%d枚選択してください", effect.Count)
+	}
+	if !player.HasCards(cards) {
+		return errors.New("持っていないカードが含まれています")
+	}
+
+	g.record(ActionResolveEffect, userID, cards)
+	player.RemoveCards(cards)
+
+	switch effect.Type {
+	case EffectSevenPass:
+		// 次の人へ渡す
+		next := g.Players[g.nextActiveIndex(g.Turn)]
+		next.Hand = append(next.Hand, cards...)
+		g.emit(Event{Type: EventSevenPassed, UserID: userID})
+	case EffectTenDiscard:
+		// 捨てたカードはそのまま除外
+		g.emit(Event{Type: EventTenDiscarded, UserID: userID, Cards: cards})
+	}
+
+	g.PendingEffect = nil
+	g.endTurn(player, effect.ClearTable, effect.ForbiddenReason)
+
+	return nil
+}
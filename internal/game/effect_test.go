@@ -0,0 +1,63 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestResolveEffectRejectsDuplicateCards(t *testing.T) {
+	tests := []struct {
+		name string
+		rank Rank
+		// 解決後の次の人の手札の枚数
+		nextCount int
+	}{
+		{"7渡し", RankSeven, 4},
+		{"10捨て", RankTen, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t, RuleSet{SevenPass: true, TenDiscard: true},
+				[]*Card{card(SuitSpade, tt.rank), card(SuitHeart, tt.rank), card(SuitSpade, 4), card(SuitSpade, 5)},
+				[]*Card{card(SuitHeart, 6), card(SuitHeart, 9)},
+			)
+
+			if err := g.Play(1, g.Players[0].Hand[:2]); err != nil {
+				t.Fatal(err)
+			}
+			if g.PendingEffect == nil || g.PendingEffect.Count != 2 {
+				t.Fatalf("2枚の選択待ちになっていない: %+v", g.PendingEffect)
+			}
+
+			giver, next := g.Players[0], g.Players[1]
+			before := [][]int{handIDs(giver), handIDs(next)}
+
+			c := giver.Hand[0]
+			if err := g.ResolveEffect(1, []*Card{c, c}); err == nil {
+				t.Fatal("同じカードを2枚として選べてしまう")
+			}
+			if after := [][]int{handIDs(giver), handIDs(next)}; !slices.EqualFunc(before, after, slices.Equal) {
+				t.Fatalf("手札が変わっている: %v -> %v", before, after)
+			}
+			if g.PendingEffect == nil {
+				t.Fatal("選択待ちが解除されている")
+			}
+
+			// 棋譜の再生でも同じ行動は弾かれる
+			if err := g.apply(&Action{Type: ActionResolveEffect, UserID: 1, CardIDs: []int{c.ID, c.ID}}); err == nil {
+				t.Fatal("棋譜から同じカードを2枚として選べてしまう")
+			}
+
+			if err := g.ResolveEffect(1, giver.Hand); err != nil {
+				t.Fatal(err)
+			}
+			if len(giver.Hand) != 0 {
+				t.Fatalf("選んだカードが手札に残っている: %v", giver.Hand)
+			}
+			if len(next.Hand) != tt.nextCount {
+				t.Fatalf("次の人の手札が %d 枚になっていない: %v", tt.nextCount, next.Hand)
+			}
+		})
+	}
+}
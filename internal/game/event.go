@@ -0,0 +1,54 @@
+package game
+
+// EventType はゲーム中に起きた出来事の種類
+type EventType string
+
+const (
+	EventGameStarted       EventType = "game_started"       // 配り終えてゲームが始まった
+	EventCardsExchanged    EventType = "cards_exchanged"    // 富豪側がカードを返した（カードは非公開）
+	EventCardsPlayed       EventType = "cards_played"       // カードが出された
+	EventSevenPassed       EventType = "seven_passed"       // 7渡し（渡したカードは非公開）
+	EventTenDiscarded      EventType = "ten_discarded"      // 10捨て
+	EventPassed            EventType = "passed"             // パスした
+	EventTableCleared      EventType = "table_cleared"      // 場が流れた
+	EventRevolutionToggled EventType = "revolution_toggled" // 革命・革命返し
+	EventElevenBack        EventType = "eleven_back"        // 11バック
+	EventSpadeThreeReturn  EventType = "spade_three_return" // スペ3返し
+	EventPlayerFinished    EventType = "player_finished"    // あがり・反則あがり・途中退出
+	EventMiyakoOchi        EventType = "miyako_ochi"        // 都落ち
+	EventGameFinished      EventType = "game_finished"      // ゲーム終了
+)
+
+// Event はゲーム中に起きた出来事
+// Seq はゲームをまたいで単調増加する通し番号
+type Event struct {
+	Seq    int64     `json:"seq"`
+	Type   EventType `json:"type"`
+	UserID int64     `json:"user_id,omitempty"`
+	Cards  []*Card   `json:"cards,omitempty"`
+	// 発生後の革命状態（RevolutionToggled・ElevenBack）
+	IsRevolution bool `json:"is_revolution,omitempty"`
+	// 確定した順位（PlayerFinished）
+	Rank int `json:"rank,omitempty"`
+	// 反則あがりや途中退出の理由（PlayerFinished）
+	Reason string `json:"reason,omitempty"`
+	// 最終順位のユーザーID（GameFinished）
+	Ranking []int64 `json:"ranking,omitempty"`
+}
+
+// EventsSince は通し番号が seq より大きいイベントを古い順に返す
+func (g *Game) EventsSince(seq int64) []*Event {
+	for i, ev := range g.Events {
+		if ev.Seq > seq {
+			return g.Events[i:]
+		}
+	}
+	return []*Event{}
+}
+
+// emit はイベントに通し番号を振って記録する
+func (g *Game) emit(ev Event) {
+	g.LastEventSeq++
+	ev.Seq = g.LastEventSeq
+	g.Events = append(g.Events, &ev)
+}
@@ -0,0 +1,144 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exchange は富豪側が返すカードの選択待ち
+type Exchange struct {
+	FromID int64 `json:"from_id"` // 返す人（大富豪・富豪）
+	ToID   int64 `json:"to_id"`   // 受け取る人（大貧民・貧民）
+	Count  int   `json:"count"`   // 返す枚数
+}
+
+// IsExchanging はカード交換の選択待ちか判定する
+func (g *Game) IsExchanging() bool {
+	return len(g.PendingExchanges) > 0
+}
+
+// startExchange は貧民側の強いカードを自動で渡し、富豪側の返却待ちにする
+func (g *Game) startExchange() {
+	g.PendingExchanges = nil
+
+	// プレイヤーをランク順に取得するためのマップ
+	rankMap := make(map[int]*Player)
+	for _, p := range g.Players {
+		rankMap[p.Rank] = p
+		sortHandForExchange(p.Hand) // 手札を強さ順にソートしておく
+	}
+
+	playerCount := len(g.Players)
+	if playerCount < 3 {
+		return // 2人以下の場合は交換なし
+	}
+
+	// 大富豪<->大貧民
+	g.takeStrongest(rankMap[1], rankMap[playerCount], 2)
+
+	// 富豪<->貧民
+	if playerCount >= 4 {
+		g.takeStrongest(rankMap[2], rankMap[playerCount-1], 1)
+	}
+}
+
+// takeStrongest は貧民側の強いカードを富豪側へ移し、返却待ちを登録する
+func (g *Game) takeStrongest(rich, poor *Player, count int) {
+	if rich == nil || poor == nil || len(poor.Hand) < count {
+		return
+	}
+
+	giveHigh := make([]*Card, count)
+	copy(giveHigh, poor.Hand[len(poor.Hand)-count:])
+
+	poor.Hand = removeCardsAtIndex(poor.Hand, len(poor.Hand)-count, len(poor.Hand))
+	rich.Hand = append(rich.Hand, giveHigh...)
+	sortHandForExchange(rich.Hand)
+
+	g.PendingExchanges = append(g.PendingExchanges, &Exchange{
+		FromID: rich.UserID,
+		ToID:   poor.UserID,
+		Count:  count,
+	})
+}
+
+// SubmitExchange は富豪側が選んだカードを貧民側へ返す
+func (g *Game) SubmitExchange(userID int64, cards []*Card) error {
+	idx := -1
+	for i, ex := range g.PendingExchanges {
+		if ex.FromID == userID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return errors.New("カードを返す必要はありません")
+	}
+	ex := g.PendingExchanges[idx]
+
+	if hasDuplicateCards(cards) {
+		return errors.New("同じカードが重複しています")
+	}
+	if len(cards) != ex.Count {
+		return fmt.Errorf("%d枚選択してください", ex.Count)
+	}
+
+	from := g.Players[g.playerIndex(ex.FromID)]
+	if !from.HasCards(cards) {
+		return errors.New("持っていないカードが含まれています")
+	}
+
+	g.record(ActionExchange, userID, cards)
+	g.giveCards(ex, cards)
+	g.PendingExchanges = append(g.PendingExchanges[:idx], g.PendingExchanges[idx+1:]...)
+	g.emit(Event{Type: EventCardsExchanged, UserID: userID})
+
+	return nil
+}
+
+// AutoExchange は返却待ちのカードを弱い順に自動で選んで返す
+func (g *Game) AutoExchange() {
+	if g.IsExchanging() {
+		g.record(ActionAutoExchange, 0, nil)
+	}
+	for _, ex := range g.PendingExchanges {
+		from := g.Players[g.playerIndex(ex.FromID)]
+		sortHandForExchange(from.Hand)
+
+		giveLow := make([]*Card, ex.Count)
+		copy(giveLow, from.Hand[:ex.Count])
+		g.giveCards(ex, giveLow)
+		g.emit(Event{Type: EventCardsExchanged, UserID: ex.FromID})
+	}
+	g.PendingExchanges = nil
+}
+
+func (g *Game) giveCards(ex *Exchange, cards []*Card) {
+	from := g.Players[g.playerIndex(ex.FromID)]
+	to := g.Players[g.playerIndex(ex.ToID)]
+
+	from.RemoveCards(cards)
+	to.Hand = append(to.Hand, cards...)
+
+	// 交換後の手札を再度ソート
+	sortHandForExchange(from.Hand)
+	sortHandForExchange(to.Hand)
+}
+
+// cancelExchanges は抜けたプレイヤーが関わる交換を取り消す
+func (g *Game) cancelExchanges(userID int64) {
+	remaining := g.PendingExchanges[:0]
+	for _, ex := range g.PendingExchanges {
+		if ex.FromID != userID && ex.ToID != userID {
+			remaining = append(remaining, ex)
+		}
+	}
+	g.PendingExchanges = remaining
+}
+
+func removeCardsAtIndex(cards []*Card, start, end int) []*Card {
+	result := make([]*Card, 0, len(cards)-(end-start))
+	result = append(result, cards[:start]...)
+	result = append(result, cards[end:]...)
+	return result
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+// newExchangeGame は前回の順位を付けて配り直し、返却待ちの状態にする
+func newExchangeGame(t *testing.T, hands ...[]*Card) *Game {
+	t.Helper()
+	players := make([]*Player, len(hands))
+	for i := range hands {
+		players[i] = newPlayer(int64(i+1), i+1)
+	}
+	g := &Game{Players: players}
+	return g.start(RuleSet{}, hands)
+}
+
+func TestSubmitExchangeRejectsDuplicateCards(t *testing.T) {
+	g := newExchangeGame(t,
+		[]*Card{card(SuitSpade, 3), card(SuitSpade, 4), card(SuitSpade, 5)},
+		[]*Card{card(SuitHeart, 6), card(SuitHeart, 7)},
+		[]*Card{card(SuitClub, RankAce), card(SuitClub, RankTwo), card(SuitClub, 8)},
+	)
+
+	// 大貧民（3）の強い2枚が大富豪（1）へ移っている
+	rich, poor := g.Players[0], g.Players[2]
+	if len(g.PendingExchanges) != 1 || g.PendingExchanges[0].Count != 2 {
+		t.Fatalf("大富豪の2枚の返却待ちになっていない: %+v", g.PendingExchanges)
+	}
+	if len(rich.Hand) != 5 || len(poor.Hand) != 1 {
+		t.Fatalf("強いカードが移っていない: %v / %v", rich.Hand, poor.Hand)
+	}
+
+	before := [][]int{handIDs(rich), handIDs(poor)}
+	c := rich.Hand[0]
+	if err := g.SubmitExchange(1, []*Card{c, c}); err == nil {
+		t.Fatal("同じカードを2枚として返せてしまう")
+	}
+	if after := [][]int{handIDs(rich), handIDs(poor)}; !slices.EqualFunc(before, after, slices.Equal) {
+		t.Fatalf("手札が変わっている: %v -> %v", before, after)
+	}
+	if !g.IsExchanging() {
+		t.Fatal("返却待ちが解除されている")
+	}
+
+	// 棋譜の再生でも同じ行動は弾かれる
+	if err := g.apply(&Action{Type: ActionExchange, UserID: 1, CardIDs: []int{c.ID, c.ID}}); err == nil {
+		t.Fatal("棋譜から同じカードを2枚として返せてしまう")
+	}
+
+	if err := g.SubmitExchange(1, rich.Hand[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if len(rich.Hand) != 3 || len(poor.Hand) != 3 {
+		t.Fatalf("交換後の枚数が合わない: %v / %v", rich.Hand, poor.Hand)
+	}
+}
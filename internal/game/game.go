@@ -3,6 +3,7 @@ package game
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 type Player struct {
@@ -10,6 +11,11 @@ type Player struct {
 	Hand   []*Card `json:"hand"`
 	Name   string  `json:"name"`
 	Rank   int     `json:"rank"`
+	IsBot  bool    `json:"is_bot"`
+	// 反則あがりの理由（通常のあがりなら空）
+	FinishReason string `json:"finish_reason"`
+	// 切断中などで、ボットが代わりに行動するか
+	AutoPlay bool `json:"auto_play"`
 }
 
 // HasCards 手札チェック
@@ -29,6 +35,19 @@ func (p *Player) HasCards(cards []*Card) bool {
 	return true
 }
 
+// hasDuplicateCards は同じカードが2回以上含まれているか判定する
+// HasCards と RemoveCards はIDごとに1回しか数えないため、先にこれで弾く
+func hasDuplicateCards(cards []*Card) bool {
+	seen := make(map[int]bool, len(cards))
+	for _, c := range cards {
+		if seen[c.ID] {
+			return true
+		}
+		seen[c.ID] = true
+	}
+	return false
+}
+
 func (p *Player) RemoveCards(cards []*Card) {
 	var newHand []*Card
 	for _, hand := range p.Hand {
@@ -53,9 +72,14 @@ type Game struct {
 	MiyakoOchiPlayer *Player
 	FieldCards       []*Card
 
+	// 反則あがりしたプレイヤー（あがった順）
+	ForbiddenFinishers []*Player
+
 	// 直前の役情報
 	LastHandType     HandType
 	LastHandStrength int
+	// 縛り中のスート（縛りがなければ空）
+	LockedSuits []Suit
 
 	LastPlayerID int64
 
@@ -63,30 +87,81 @@ type Game struct {
 	IsRevolution bool
 	PassCount    int
 
+	// 手番の進む向き（1: 正順, -1: 逆順）
+	Direction int
+	// 次の手番で飛ばす人数
+	SkipCount int
+
 	IsFinished bool
+
+	// 適用中のローカルルール
+	Rules RuleSet
+
+	// プレイヤーの選択待ちの効果（なければ nil）
+	PendingEffect *PendingEffect
+
+	// カード交換の返却待ち
+	PendingExchanges []*Exchange
+	// 返却待ちの期限（過ぎたら自動で返す。ゼロ値なら期限なし）
+	ExchangeDeadline time.Time
+
+	// 現在の手番（交換・効果の選択を含む）の期限（ゼロ値なら期限なし）
+	TurnDeadline time.Time
+
+	// シャッフルに使った乱数の種（終了するまで公開しない）
+	Seed string
+	// 開始時に公開する種のハッシュ
+	SeedHash string
+
+	// 配られた手札と全ての行動の記録
+	Record *Record
+	// 保存した記録のID（保存前は0）
+	RecordID int64
+	// 棋譜の再生用に作られたゲームか（乱数の種も公開する）
+	IsReplay bool `json:"-"`
+	// 全員の手札を公開する表示用のゲームか（棋譜の再生・観戦者向けの遅延表示）
+	RevealHands bool `json:"-"`
+	// 観戦者に全員の手札を見せる場合に遅らせる手数（0なら見せない）
+	// 途中で切り替えると、それまでに届いた出来事と合わせて手札が分かってしまうため、開始時に固定する
+	SpectatorDelay int
+
+	// 現在のゲームで起きた出来事（古い順）
+	Events []*Event
+	// 最後に振ったイベントの通し番号（リセットしても戻さない）
+	LastEventSeq int64
+}
+
+// IsBotID はボットの席か判定する（ボットのIDは負の値で表す）
+func IsBotID(userID int64) bool {
+	return userID < 0
 }
 
-func NewGame(memberIDs []int64) *Game {
-	// 初期化処理
-	deck := NewDeck(2)
-	deck.Shuffle()
-	hands := deck.Deal(len(memberIDs))
+func NewGame(memberIDs []int64, rules RuleSet) *Game {
+	return NewGameWithSeed(memberIDs, rules, NewSeed())
+}
 
+// NewGameWithSeed は指定した種で配ったゲームを作る（不具合の再現やテスト用）
+func NewGameWithSeed(memberIDs []int64, rules RuleSet, seed Seed) *Game {
 	players := make([]*Player, len(memberIDs))
 	for i, uid := range memberIDs {
-		players[i] = &Player{
-			UserID: uid,
-			Hand:   hands[i],
-			Name:   fmt.Sprintf("User%d", uid),
-			Rank:   0,
-		}
+		players[i] = newPlayer(uid, 0)
 	}
 
-	return &Game{
-		Players:    players,
-		FieldCards: []*Card{},
-		Turn:       0,
+	g := &Game{Players: players}
+	return g.ResetWithSeed(rules, seed)
+}
+
+func newPlayer(userID int64, rank int) *Player {
+	p := &Player{
+		UserID: userID,
+		Name:   fmt.Sprintf("User%d", userID),
+		Rank:   rank,
 	}
+	if IsBotID(userID) {
+		p.IsBot = true
+		p.Name = fmt.Sprintf("CPU%d", -userID)
+	}
+	return p
 }
 
 // Play カードを出す
@@ -98,48 +173,50 @@ func (g *Game) Play(userID int64, cards []*Card) error {
 		return errors.New("あなたのターンではありません")
 	}
 
-	// 手札所有チェック
-	if !player.HasCards(cards) {
-		return errors.New("持っていないカードが含まれています")
+	// カード交換の完了チェック
+	if g.IsExchanging() {
+		return errors.New("カード交換中です")
 	}
 
-	is11Back := false
-	for _, c := range g.FieldCards {
-		if c.Rank == RankJack {
-			is11Back = true
-			break
-		}
+	// 効果の選択待ちチェック
+	if g.PendingEffect != nil {
+		return errors.New("効果の選択待ちです")
 	}
 
-	effectiveRev := g.IsRevolution
-	if is11Back {
-		effectiveRev = !effectiveRev
+	// 手札所有チェック
+	if hasDuplicateCards(cards) {
+		return errors.New("同じカードが重複しています")
+	}
+	if !player.HasCards(cards) {
+		return errors.New("持っていないカードが含まれています")
 	}
 
-	// 役の解析
-	hType, strength, err := AnalyzeHand(cards, effectiveRev)
+	effectiveRev := g.EffectiveRevolution()
+
+	hType, strength, err := g.checkPlay(cards, effectiveRev)
 	if err != nil {
 		return err
 	}
+	g.record(ActionPlay, userID, cards)
 
-	fieldStrength := 0
-	fieldType := g.LastHandType
-	if len(g.FieldCards) > 0 {
-		// 場にあるカードを今のルールで再評価
-		ft, fStr, err := AnalyzeHand(g.FieldCards, effectiveRev)
-		if err == nil {
-			fieldStrength = fStr
-			fieldType = ft
-		}
+	// 反則あがり判定（出す前の革命状態で判定する）
+	forbiddenReason := ""
+	if g.Rules.ForbiddenFinish {
+		forbiddenReason = forbiddenFinishReason(cards, effectiveRev, g.Rules)
 	}
 
-	// ルール判定
-	// 場が流れているならチェック不要
-	if err := ValidatePlay(g.FieldCards, fieldType, fieldStrength, cards, hType, strength); err != nil {
-		return err
+	// 縛りの発生判定
+	if g.Rules.Shibari && len(g.LockedSuits) == 0 {
+		g.LockedSuits = lockSuits(g.FieldCards, cards)
 	}
 
+	isSpadeThree := isSpadeThreeReturn(g.FieldCards, cards, g.Rules)
+
 	player.RemoveCards(cards)
+	g.emit(Event{Type: EventCardsPlayed, UserID: userID, Cards: cards})
+	if isSpadeThree {
+		g.emit(Event{Type: EventSpadeThreeReturn, UserID: userID})
+	}
 
 	// 場の更新
 	g.FieldCards = cards
@@ -150,31 +227,97 @@ func (g *Game) Play(userID int64, cards []*Card) error {
 
 	// 特殊効果
 	// 革命
-	if len(cards) >= 4 {
+	if g.Rules.Revolution && len(cards) >= 4 {
 		g.IsRevolution = !g.IsRevolution
+		g.emit(Event{Type: EventRevolutionToggled, UserID: userID, IsRevolution: g.IsRevolution})
+	}
+
+	// 11バック（場が流れるまで有効）
+	if g.Rules.ElevenBack && countRank(cards, RankJack) > 0 {
+		g.emit(Event{Type: EventElevenBack, UserID: userID, IsRevolution: g.EffectiveRevolution()})
+	}
+
+	// 9リバース
+	if g.Rules.NineReverse && countRank(cards, RankNine) > 0 {
+		g.Direction = -g.step()
+	}
+
+	// 5スキップ
+	if g.Rules.FiveSkip {
+		g.SkipCount = countRank(cards, RankFive)
 	}
 
 	// 8切り判定
 	is8giri := false
-	for _, c := range cards {
-		if c.Rank == RankEight {
-			is8giri = true
-			break
+	if g.Rules.EightCut {
+		for _, c := range cards {
+			if c.Rank == RankEight {
+				is8giri = true
+				break
+			}
 		}
 	}
 
+	// 7渡し・10捨て
+	// 選択が終わるまでターンを進めない
+	if effect := g.detectEffect(player, cards); effect != nil {
+		effect.ClearTable = is8giri
+		effect.ForbiddenReason = forbiddenReason
+		g.PendingEffect = effect
+		return nil
+	}
+
+	g.endTurn(player, is8giri, forbiddenReason)
+
+	return nil
+}
+
+// checkPlay は出し札の役を解析し、場に出せるか判定する
+func (g *Game) checkPlay(cards []*Card, effectiveRev bool) (HandType, int, error) {
+	// 役の解析
+	hType, strength, err := AnalyzeHand(cards, effectiveRev)
+	if err != nil {
+		return HandTypeInvalid, 0, err
+	}
+
+	fieldStrength := 0
+	fieldType := g.LastHandType
+	if len(g.FieldCards) > 0 {
+		// 場にあるカードを今のルールで再評価
+		ft, fStr, err := AnalyzeHand(g.FieldCards, effectiveRev)
+		if err == nil {
+			fieldStrength = fStr
+			fieldType = ft
+		}
+	}
+
+	// ルール判定
+	// 場が流れているならチェック不要
+	if err := ValidatePlay(g.FieldCards, fieldType, fieldStrength, cards, hType, strength, g.LockedSuits, g.Rules); err != nil {
+		return HandTypeInvalid, 0, err
+	}
+
+	return hType, strength, nil
+}
+
+// endTurn はあがり判定を行い、次の手番へ進める
+func (g *Game) endTurn(player *Player, is8giri bool, forbiddenReason string) {
 	// あがり判定
 	if len(player.Hand) == 0 {
-		g.handleWin(player)
+		if forbiddenReason != "" {
+			g.handleForbiddenFinish(player, forbiddenReason)
+		} else {
+			g.handleWin(player)
+		}
 
 		// ゲーム終了判定
 		if g.IsFinished {
-			return nil // 終了
+			return // 終了
 		}
 
 		// あがった場合は次の人へ
 		g.advanceTurn()
-		return nil
+		return
 	}
 
 	// 8切りならターン継続、それ以外なら次へ
@@ -183,37 +326,66 @@ func (g *Game) Play(userID int64, cards []*Card) error {
 	} else {
 		g.advanceTurn()
 	}
+}
 
-	return nil
+func (g *Game) Reset(rules RuleSet) *Game {
+	return g.ResetWithSeed(rules, NewSeed())
 }
 
-func (g *Game) Reset() *Game {
-	// デッキの再生成とシャッフル
-	deck := NewDeck(2)
-	deck.Shuffle()
+// ResetWithSeed は指定した種でシャッフルして配り直す
+// 種は終了まで伏せ、ハッシュだけを公開する
+func (g *Game) ResetWithSeed(rules RuleSet, seed Seed) *Game {
+	// デッキの再生成とシャッフルをして、カードを配る
+	hands := DealFromSeed(seed, rules.JokerCount, len(g.Players))
+
+	g.start(rules, hands)
 
-	// カードを配る
-	hands := deck.Deal(len(g.Players))
+	g.Seed = seed.String()
+	g.SeedHash = seed.Hash()
+	g.Record.Seed = g.Seed
+	g.Record.SeedHash = g.SeedHash
+
+	return g
+}
+
+// start は配られた手札でゲームを開始する
+// 棋譜の再生でも同じ手順で初期状態を作る
+func (g *Game) start(rules RuleSet, hands [][]*Card) *Game {
+	g.Rules = rules
+	g.Record = newRecord(g.Players, rules, hands)
+	g.RecordID = 0
 
 	// プレイヤー状態のリセット
 	for i, p := range g.Players {
 		p.Hand = hands[i]
+		p.FinishReason = ""
+		p.AutoPlay = false
 	}
 
 	// ゲーム状態の初期化
 	g.FinishedPlayers = []*Player{}
+	g.ForbiddenFinishers = nil
 	g.FieldCards = []*Card{}
 	g.LastHandType = HandTypeInvalid
 	g.LastHandStrength = 0
+	g.LockedSuits = nil
 	g.LastPlayerID = 0
 	g.IsRevolution = false
 	g.PassCount = 0
+	g.Direction = 1
+	g.SkipCount = 0
 	g.IsFinished = false
 	g.MiyakoOchiPlayer = nil
+	g.PendingEffect = nil
+	g.PendingExchanges = nil
+	g.ExchangeDeadline = time.Time{}
+	g.TurnDeadline = time.Time{}
+	g.Events = nil
 
 	// カード交換
+	// 貧民側のカードはここで渡し、富豪側は SubmitExchange で返す
 	if g.Players[0].Rank > 0 {
-		g.exchangeCards()
+		g.startExchange()
 	}
 
 	// ターンの決定
@@ -226,6 +398,8 @@ func (g *Game) Reset() *Game {
 		}
 	}
 
+	g.emit(Event{Type: EventGameStarted})
+
 	return g
 }
 
@@ -235,42 +409,210 @@ func (g *Game) Pass(userID int64) error {
 	if player.UserID != userID {
 		return errors.New("あなたのターンではありません")
 	}
+	if g.IsExchanging() {
+		return errors.New("カード交換中です")
+	}
+	if g.PendingEffect != nil {
+		return errors.New("効果の選択待ちです")
+	}
+	// 親は必ず何か出す（パスで親が回ってくると、他の人の手番を飛ばしてしまう）
+	if len(g.FieldCards) == 0 {
+		return errors.New("場にカードがないためパスできません")
+	}
 
+	g.record(ActionPass, userID, nil)
 	g.PassCount++
+	g.emit(Event{Type: EventPassed, UserID: userID})
 	g.advanceTurn()
 
-	// 全員パス判定 (プレイ人数 - 1)
-	activeCount := g.getActivePlayerCount()
-	if activeCount > 0 && g.PassCount >= activeCount-1 {
-		g.clearTable()
+	// 全員パス判定
+	if g.isAllPassed() {
 		// パスで流れたら、親（最後にカードを出した人）のターンにする
-		// ただし今回は簡易的に、現在手番の人を親としてスタートさせる
+		g.clearTable()
+		g.Turn = g.leadIndex()
 	}
 
 	return nil
 }
 
+// EffectiveRevolution は11バックを考慮した現在の革命状態を返す
+func (g *Game) EffectiveRevolution() bool {
+	if g.IsElevenBack() {
+		return !g.IsRevolution
+	}
+	return g.IsRevolution
+}
+
+// IsElevenBack は場の11によって強さが逆転している最中か判定する
+func (g *Game) IsElevenBack() bool {
+	return g.Rules.ElevenBack && countRank(g.FieldCards, RankJack) > 0
+}
+
+// Phase はゲームの進行段階
+type Phase string
+
+const (
+	PhaseDealing  Phase = "dealing"  // 配札前
+	PhaseExchange Phase = "exchange" // カード交換中
+	PhasePlaying  Phase = "playing"  // 対戦中
+	PhaseFinished Phase = "finished" // 終了
+)
+
+// Phase は現在の進行段階を返す
+func (g *Game) Phase() Phase {
+	switch {
+	case g.IsFinished:
+		return PhaseFinished
+	case g.getActivePlayerCount() == 0:
+		return PhaseDealing
+	case g.IsExchanging():
+		return PhaseExchange
+	default:
+		return PhasePlaying
+	}
+}
+
+// WaitingUserIDs は入力を待っているプレイヤーのIDを返す
+// 交換中は返却待ちの全員、効果の選択待ちならそのプレイヤー、それ以外は手番のプレイヤー
+func (g *Game) WaitingUserIDs() []int64 {
+	if g.IsFinished || len(g.Players) == 0 {
+		return nil
+	}
+	if g.IsExchanging() {
+		ids := make([]int64, len(g.PendingExchanges))
+		for i, ex := range g.PendingExchanges {
+			ids[i] = ex.FromID
+		}
+		return ids
+	}
+	if g.PendingEffect != nil {
+		return []int64{g.PendingEffect.UserID}
+	}
+	return []int64{g.Players[g.Turn].UserID}
+}
+
+// IsBotControlled はボットが行動する席か判定する（ボットの席と、代打ち中の人間の席）
+func (g *Game) IsBotControlled(userID int64) bool {
+	if IsBotID(userID) {
+		return true
+	}
+	idx := g.playerIndex(userID)
+	return idx >= 0 && g.Players[idx].AutoPlay
+}
+
+// SetAutoPlay は人間の席をボットに代打ちさせるか切り替える
+func (g *Game) SetAutoPlay(userID int64, enabled bool) error {
+	idx := g.playerIndex(userID)
+	if idx < 0 {
+		return errors.New("プレイヤーが見つかりません")
+	}
+	player := g.Players[idx]
+	if player.IsBot {
+		return errors.New("ボットの席です")
+	}
+	player.AutoPlay = enabled
+	return nil
+}
+
+// CurrentPlayerID は手番のプレイヤーのIDを返す（終了後は0）
+func (g *Game) CurrentPlayerID() int64 {
+	if g.IsFinished || len(g.Players) == 0 {
+		return 0
+	}
+	return g.Players[g.Turn].UserID
+}
+
 func (g *Game) clearTable() {
 	g.FieldCards = []*Card{}
 	g.LastHandType = HandTypeInvalid
 	g.LastHandStrength = 0
+	g.LockedSuits = nil
 	g.PassCount = 0
+	g.SkipCount = 0
+	g.emit(Event{Type: EventTableCleared})
+}
+
+// isAllPassed は最後に出した人以外が全員パスしたか判定する
+// 最後に出した人があがっている場合は、残り全員のパスが必要
+func (g *Game) isAllPassed() bool {
+	activeCount := g.getActivePlayerCount()
+	if activeCount == 0 {
+		return false
+	}
+
+	need := activeCount
+	if i := g.playerIndex(g.LastPlayerID); i >= 0 && len(g.Players[i].Hand) > 0 {
+		need--
+	}
+	return g.PassCount >= need
+}
+
+// leadIndex は場が流れたときに親になるプレイヤーの位置を返す
+// 最後に出した人があがっていれば、その次の席の人が親になる
+func (g *Game) leadIndex() int {
+	i := g.playerIndex(g.LastPlayerID)
+	if i < 0 {
+		return g.Turn
+	}
+	if len(g.Players[i].Hand) > 0 {
+		return i
+	}
+	return g.nextActiveIndex(i)
+}
+
+// playerIndex はユーザーIDから席の位置を返す（いなければ -1）
+func (g *Game) playerIndex(userID int64) int {
+	for i, p := range g.Players {
+		if p.UserID == userID {
+			return i
+		}
+	}
+	return -1
 }
 
 func (g *Game) advanceTurn() {
-	startTurn := g.Turn
+	g.Turn = g.nextActiveIndex(g.Turn)
+
+	if g.SkipCount == 0 {
+		return
+	}
+
+	// 5スキップ: 飛ばされた人はパスしたものとして扱う
+	for ; g.SkipCount > 0; g.SkipCount-- {
+		g.Turn = g.nextActiveIndex(g.Turn)
+		g.PassCount++
+	}
+	if g.isAllPassed() {
+		g.clearTable()
+		g.Turn = g.leadIndex()
+	}
+}
+
+// step は手番の進む向きを返す
+func (g *Game) step() int {
+	if g.Direction < 0 {
+		return -1
+	}
+	return 1
+}
+
+// nextActiveIndex は from の次に手番が回るプレイヤーの位置を返す
+func (g *Game) nextActiveIndex(from int) int {
+	i := from
 	for {
-		g.Turn++
-		if g.Turn >= len(g.Players) {
-			g.Turn = 0
+		i += g.step()
+		if i >= len(g.Players) {
+			i = 0
+		} else if i < 0 {
+			i = len(g.Players) - 1
 		}
 		// まだあがっていない人ならOK
-		if len(g.Players[g.Turn].Hand) > 0 {
-			break
+		if len(g.Players[i].Hand) > 0 {
+			return i
 		}
 		// 一周したら終了
-		if g.Turn == startTurn {
-			break
+		if i == from {
+			return i
 		}
 	}
 }
@@ -279,11 +621,13 @@ func (g *Game) handleWin(winner *Player) {
 	// 順位リストに追加
 	g.FinishedPlayers = append(g.FinishedPlayers, winner)
 
-	// 順位付け
+	// 順位付け（都落ちは前回の順位で判定するため、上書きする前に控えておく）
+	prevRank := winner.Rank
 	winner.Rank = len(g.FinishedPlayers)
+	g.emit(Event{Type: EventPlayerFinished, UserID: winner.UserID, Rank: winner.Rank})
 
 	// 都落ち判定
-	if len(g.FinishedPlayers) == 1 && winner.Rank != 1 {
+	if g.Rules.MiyakoOchi && len(g.FinishedPlayers) == 1 && prevRank != 1 {
 		for _, p := range g.Players {
 			if p.Rank == 1 && len(p.Hand) > 0 {
 				// 都落ち発生！
@@ -293,8 +637,21 @@ func (g *Game) handleWin(winner *Player) {
 		}
 	}
 
+	// ゲーム終了判定（2人で都落ちした場合は誰も残らない）
+	if g.getActivePlayerCount() <= 1 {
+		g.finishGame()
+	}
+}
+
+// handleForbiddenFinish は反則あがりしたプレイヤーを最下位側に回す
+// 通常のあがりとは扱わないため、都落ちは発生しない
+func (g *Game) handleForbiddenFinish(player *Player, reason string) {
+	player.FinishReason = reason
+	g.ForbiddenFinishers = append(g.ForbiddenFinishers, player)
+	g.emit(Event{Type: EventPlayerFinished, UserID: player.UserID, Reason: reason})
+
 	// ゲーム終了判定
-	if g.getActivePlayerCount() == 1 {
+	if g.getActivePlayerCount() <= 1 {
 		g.finishGame()
 	}
 }
@@ -305,7 +662,7 @@ func (g *Game) triggerMiyakoOchi(loser *Player) {
 
 	// 一時退避
 	g.MiyakoOchiPlayer = loser
-	// ※ ここでクライアントに都落ち発生を通知するイベントを追加
+	g.emit(Event{Type: EventMiyakoOchi, UserID: loser.UserID})
 }
 
 func (g *Game) finishGame() {
@@ -331,10 +688,19 @@ func (g *Game) finishGame() {
 		g.MiyakoOchiPlayer = nil // リセット
 	}
 
+	// 反則あがりの人は最下位から順に並べる（先に反則した人ほど下位）
+	for i := len(g.ForbiddenFinishers) - 1; i >= 0; i-- {
+		g.FinishedPlayers = append(g.FinishedPlayers, g.ForbiddenFinishers[i])
+	}
+	g.ForbiddenFinishers = nil
+
 	// 次のゲームのために Rank を確定させる
+	ranking := make([]int64, len(g.FinishedPlayers))
 	for i, p := range g.FinishedPlayers {
 		p.Rank = i + 1 // 1位, 2位...
+		ranking[i] = p.UserID
 	}
+	g.emit(Event{Type: EventGameFinished, Ranking: ranking})
 }
 
 func (g *Game) getActivePlayerCount() int {
@@ -347,78 +713,6 @@ func (g *Game) getActivePlayerCount() int {
 	return c
 }
 
-func (g *Game) exchangeCards() {
-	// プレイヤーをランク順に取得するためのマップ
-	rankMap := make(map[int]*Player)
-	for _, p := range g.Players {
-		rankMap[p.Rank] = p
-		sortHandForExchange(p.Hand) // 手札を強さ順にソートしておく
-	}
-
-	playerCount := len(g.Players)
-	if playerCount < 3 {
-		return // 2人以下の場合は交換なし
-	}
-
-	// 大富豪<->大貧民
-	daifugo := rankMap[1]
-	daihinmin := rankMap[playerCount]
-
-	if daifugo != nil && daihinmin != nil {
-		// 大富豪
-		giveLow := daifugo.Hand[:2]
-		// 大貧民
-		giveHigh := daihinmin.Hand[len(daihinmin.Hand)-2:]
-
-		// 交換実行
-		cardsFromDaifugo := make([]*Card, 2)
-		copy(cardsFromDaifugo, giveLow)
-
-		cardsFromDaihinmin := make([]*Card, 2)
-		copy(cardsFromDaihinmin, giveHigh)
-
-		// 手札から削除
-		daifugo.Hand = removeCardsAtIndex(daifugo.Hand, 0, 2)
-		daihinmin.Hand = removeCardsAtIndex(daihinmin.Hand, len(daihinmin.Hand)-2, len(daihinmin.Hand))
-
-		// 手札に追加
-		daifugo.Hand = append(daifugo.Hand, cardsFromDaihinmin...)
-		daihinmin.Hand = append(daihinmin.Hand, cardsFromDaifugo...)
-	}
-
-	// 富豪<->貧民
-	if playerCount >= 4 {
-		fugo := rankMap[2]
-		hinmin := rankMap[playerCount-1]
-
-		if fugo != nil && hinmin != nil {
-			giveLow := fugo.Hand[:1]
-			giveHigh := hinmin.Hand[len(hinmin.Hand)-1:]
-
-			cardFromFugo := giveLow[0]
-			cardFromHinmin := giveHigh[0]
-
-			fugo.Hand = removeCardsAtIndex(fugo.Hand, 0, 1)
-			hinmin.Hand = removeCardsAtIndex(hinmin.Hand, len(hinmin.Hand)-1, len(hinmin.Hand))
-
-			fugo.Hand = append(fugo.Hand, cardFromHinmin)
-			hinmin.Hand = append(hinmin.Hand, cardFromFugo)
-		}
-	}
-
-	// 交換後の手札を再度ソート
-	for _, p := range g.Players {
-		sortHandForExchange(p.Hand)
-	}
-}
-
-func removeCardsAtIndex(cards []*Card, start, end int) []*Card {
-	result := make([]*Card, 0, len(cards)-(end-start))
-	result = append(result, cards[:start]...)
-	result = append(result, cards[end:]...)
-	return result
-}
-
 func (g *Game) RemovePlayer(userID int64) {
 	// 既に終了したプレイヤーなら何もしない
 	for _, p := range g.FinishedPlayers {
@@ -426,41 +720,65 @@ func (g *Game) RemovePlayer(userID int64) {
 			return
 		}
 	}
-
-	var target *Player
-	var targetIndex int
-	for i, p := range g.Players {
+	for _, p := range g.ForbiddenFinishers {
 		if p.UserID == userID {
-			target = p
-			targetIndex = i
-			break
+			return
 		}
 	}
+	// 都落ちしたプレイヤーは最下位が確定している
+	if g.MiyakoOchiPlayer != nil && g.MiyakoOchiPlayer.UserID == userID {
+		return
+	}
 
-	if target == nil {
+	targetIndex := g.playerIndex(userID)
+	if targetIndex < 0 {
 		return
 	}
+	target := g.Players[targetIndex]
+	g.record(ActionLeave, userID, nil)
 
 	// 手札を破棄
 	target.Hand = []*Card{}
 
+	// 抜けた後の手番と親を席の並びから決めておく
+	turnID := g.Players[g.Turn].UserID
+	if g.Turn == targetIndex {
+		turnID = g.Players[g.nextActiveIndex(targetIndex)].UserID
+	}
+	if g.LastPlayerID == userID {
+		// 最後に出した人が抜けた場合は、次の席の人を親とみなす
+		g.LastPlayerID = g.Players[g.nextActiveIndex(targetIndex)].UserID
+	}
+
+	// 抜けたプレイヤーの選択待ちは取り消す
+	if g.PendingEffect != nil && g.PendingEffect.UserID == userID {
+		g.PendingEffect = nil
+	}
+	g.cancelExchanges(userID)
+
 	// 順位を確定
 	g.FinishedPlayers = append(g.FinishedPlayers, target)
+	g.emit(Event{Type: EventPlayerFinished, UserID: userID, Reason: "途中退出"})
 
 	// Playersリストから削除
 	g.Players = append(g.Players[:targetIndex], g.Players[targetIndex+1:]...)
 
 	// もしターンプレイヤーが抜けた場合、次の人へ
-	if g.Turn == targetIndex {
-		if g.Turn >= len(g.Players) {
-			g.Turn = 0
-		}
-	} else if g.Turn > targetIndex {
-		g.Turn--
+	if i := g.playerIndex(turnID); i >= 0 {
+		g.Turn = i
+	} else {
+		g.Turn = 0
 	}
 
 	// 残り1人になったらゲーム終了
 	if g.getActivePlayerCount() <= 1 {
 		g.finishGame()
+		return
+	}
+
+	// 抜けたことで全員パスが成立した場合は場を流す
+	if len(g.FieldCards) > 0 && g.isAllPassed() {
+		g.clearTable()
+		g.Turn = g.leadIndex()
 	}
 }
@@ -0,0 +1,223 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+// card はデッキと同じIDのカードを作る
+func card(s Suit, r Rank) *Card {
+	return NewCard(int(s)*13+int(r), s, r)
+}
+
+// joker は n 枚目のジョーカーを作る
+func joker(n int) *Card {
+	return NewCard(52+n, SuitJoker, 0)
+}
+
+// newTestGame は指定した手札を配ったゲームを作る（ユーザーIDは1から順に振る）
+func newTestGame(t *testing.T, rules RuleSet, hands ...[]*Card) *Game {
+	t.Helper()
+	players := make([]*Player, len(hands))
+	for i := range hands {
+		players[i] = newPlayer(int64(i+1), 0)
+	}
+	g := &Game{Players: players}
+	return g.start(rules, hands)
+}
+
+// handIDs は手札のIDを並び順のまま返す
+func handIDs(p *Player) []int {
+	ids := make([]int, len(p.Hand))
+	for i, c := range p.Hand {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+func TestPlayRejectsDuplicateCards(t *testing.T) {
+	g := newTestGame(t, DefaultRuleSet(),
+		[]*Card{card(SuitSpade, 4), card(SuitSpade, 5)},
+		[]*Card{card(SuitHeart, 6), card(SuitHeart, 7)},
+	)
+
+	c := g.Players[0].Hand[0]
+	if err := g.Play(1, []*Card{c, c}); err == nil {
+		t.Fatal("同じカード2枚のペアが出せてしまう")
+	}
+	if len(g.Players[0].Hand) != 2 {
+		t.Fatalf("手札が変わっている: %v", g.Players[0].Hand)
+	}
+}
+
+// setRanks は前回の順位を設定する（カード交換は行わない）
+func setRanks(g *Game, ranks ...int) {
+	for i, r := range ranks {
+		g.Players[i].Rank = r
+	}
+}
+
+// finishedIDs はあがった順のユーザーIDを返す
+func finishedIDs(g *Game) []int64 {
+	ids := make([]int64, len(g.FinishedPlayers))
+	for i, p := range g.FinishedPlayers {
+		ids[i] = p.UserID
+	}
+	return ids
+}
+
+func hasEvent(g *Game, typ EventType, userID int64) bool {
+	for _, ev := range g.Events {
+		if ev.Type == typ && ev.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMiyakoOchi(t *testing.T) {
+	tests := []struct {
+		name       string
+		miyakoOchi bool
+		players    int
+		// 最初にあがるプレイヤーの席
+		first        int
+		wantMiyako   bool
+		wantFinished []int64
+	}{
+		{"大富豪以外が1位", true, 3, 1, true, []int64{2, 3, 1}},
+		{"大富豪が1位", true, 3, 0, false, nil},
+		{"都落ちなし", false, 3, 1, false, nil},
+		// 都落ちで誰も残らない場合も終了する
+		{"2人で大富豪以外が1位", true, 2, 1, true, []int64{2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hands := [][]*Card{
+				{card(SuitSpade, 4), card(SuitSpade, 5)},
+				{card(SuitSpade, 6), card(SuitSpade, 7)},
+				{card(SuitSpade, 9), card(SuitSpade, 10)},
+			}[:tt.players]
+			// 最初にあがるプレイヤーだけ1枚にする
+			hands[tt.first] = []*Card{card(SuitHeart, RankKing)}
+
+			g := newTestGame(t, RuleSet{MiyakoOchi: tt.miyakoOchi}, hands...)
+			setRanks(g, []int{1, 2, 3}[:tt.players]...)
+			g.Turn = tt.first
+
+			winner := g.Players[tt.first]
+			if err := g.Play(winner.UserID, winner.Hand); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := hasEvent(g, EventMiyakoOchi, 1); got != tt.wantMiyako {
+				t.Fatalf("都落ちのイベント = %v, want %v", got, tt.wantMiyako)
+			}
+			if !tt.wantMiyako {
+				if g.MiyakoOchiPlayer != nil || g.IsFinished {
+					t.Fatal("都落ちしていないのに大富豪が抜けている")
+				}
+				return
+			}
+
+			if !g.IsFinished {
+				t.Fatal("都落ちで残り1人以下になったのに終了していない")
+			}
+			if got := finishedIDs(g); !slices.Equal(got, tt.wantFinished) {
+				t.Fatalf("順位 = %v, want %v", got, tt.wantFinished)
+			}
+			if g.Players[0].Rank != tt.players {
+				t.Fatalf("都落ちした大富豪の順位 = %d, want %d", g.Players[0].Rank, tt.players)
+			}
+		})
+	}
+}
+
+func TestForbiddenFinishWithMiyakoOchi(t *testing.T) {
+	tests := []struct {
+		name  string
+		hands [][]*Card
+		// 1枚ずつ出してあがる席の順
+		order        []int
+		wantMiyako   bool
+		wantFinished []int64
+		// 反則あがりになる席
+		forbidden []int
+	}{
+		{
+			name: "最初に反則あがり、次に大富豪以外があがる",
+			hands: [][]*Card{
+				{card(SuitSpade, 4), card(SuitSpade, 5)},
+				{card(SuitHeart, RankKing)},
+				{card(SuitSpade, RankTwo)},
+				{card(SuitSpade, 9), card(SuitSpade, 10)},
+			},
+			order:        []int{2, 1},
+			wantMiyako:   true,
+			wantFinished: []int64{2, 4, 1, 3},
+			forbidden:    []int{2},
+		},
+		{
+			name: "都落ちの後に反則あがり",
+			hands: [][]*Card{
+				{card(SuitSpade, 4), card(SuitSpade, 5)},
+				{card(SuitHeart, RankKing)},
+				{card(SuitSpade, RankTwo)},
+				{card(SuitSpade, 9), card(SuitSpade, 10)},
+			},
+			order:        []int{1, 2},
+			wantMiyako:   true,
+			wantFinished: []int64{2, 4, 1, 3},
+			forbidden:    []int{2},
+		},
+		{
+			name: "大富豪が最初に反則あがり",
+			hands: [][]*Card{
+				{joker(1)},
+				{card(SuitHeart, RankKing)},
+				{card(SuitHeart, RankQueen)},
+				{card(SuitSpade, 9), card(SuitSpade, 10)},
+			},
+			order:        []int{0, 1, 2},
+			wantMiyako:   false,
+			wantFinished: []int64{2, 3, 4, 1},
+			forbidden:    []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t, RuleSet{MiyakoOchi: true, ForbiddenFinish: true, JokerCount: 1}, tt.hands...)
+			setRanks(g, 1, 2, 3, 4)
+
+			for _, seat := range tt.order {
+				p := g.Players[seat]
+				g.Turn = seat
+				g.clearTable()
+				if err := g.Play(p.UserID, p.Hand); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := hasEvent(g, EventMiyakoOchi, 1); got != tt.wantMiyako {
+				t.Fatalf("都落ちのイベント = %v, want %v", got, tt.wantMiyako)
+			}
+			if !g.IsFinished {
+				t.Fatal("都落ちで残り1人以下になったのに終了していない")
+			}
+			if got := finishedIDs(g); !slices.Equal(got, tt.wantFinished) {
+				t.Fatalf("順位 = %v, want %v", got, tt.wantFinished)
+			}
+			for _, seat := range tt.forbidden {
+				p := g.Players[seat]
+				if p.FinishReason == "" {
+					t.Errorf("ユーザー %d に反則あがりの理由がない", p.UserID)
+				}
+				if p.Rank != len(g.Players) {
+					t.Errorf("反則あがりしたユーザー %d の順位 = %d, want %d", p.UserID, p.Rank, len(g.Players))
+				}
+			}
+		})
+	}
+}
@@ -22,12 +22,43 @@ func IsPair(cards []*Card) bool {
 	return true
 }
 
+// 階段で使える並び順の最大値
+const maxSeqRank = 13
+
+// toSeqRank は階段判定用の並び順を返す（3=1 … K=11, A=12, 2=13）
 func toSeqRank(c *Card) int {
-	return int(c.Rank)
+	switch c.Rank {
+	case RankAce:
+		return 12
+	case RankTwo:
+		return 13
+	default:
+		return int(c.Rank) - 2
+	}
+}
+
+// seqRankRange はジョーカー以外のカードの並び順の最小値と最大値を返す
+func seqRankRange(cards []*Card) (int, int) {
+	lo, hi := maxSeqRank+1, 0
+	for _, c := range cards {
+		if c.Suit == SuitJoker {
+			continue
+		}
+		r := toSeqRank(c)
+		if r < lo {
+			lo = r
+		}
+		if r > hi {
+			hi = r
+		}
+	}
+	return lo, hi
 }
 
+// IsSequence は 3<…<K<A<2 の並びで階段になっているか判定する
+// 余ったジョーカーは両端のどちらにも置ける（2の上、3の下には続かない）
 func IsSequence(cards []*Card) bool {
-	if len(cards) < 3 {
+	if len(cards) < 3 || len(cards) > maxSeqRank {
 		return false
 	}
 	var normalCards []*Card
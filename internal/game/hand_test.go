@@ -0,0 +1,99 @@
+package game
+
+import "testing"
+
+func TestIsSequence(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards []*Card
+		want  bool
+	}{
+		{"3-4-5", []*Card{card(SuitSpade, 3), card(SuitSpade, 4), card(SuitSpade, 5)}, true},
+		{"並びが順不同", []*Card{card(SuitSpade, 5), card(SuitSpade, 3), card(SuitSpade, 4)}, true},
+		{"Q-K-A", []*Card{card(SuitSpade, RankQueen), card(SuitSpade, RankKing), card(SuitSpade, RankAce)}, true},
+		{"K-A-2", []*Card{card(SuitSpade, RankKing), card(SuitSpade, RankAce), card(SuitSpade, RankTwo)}, true},
+		{"A-2-3は続かない", []*Card{card(SuitSpade, RankAce), card(SuitSpade, RankTwo), card(SuitSpade, 3)}, false},
+		{"2-3-4は続かない", []*Card{card(SuitSpade, RankTwo), card(SuitSpade, 3), card(SuitSpade, 4)}, false},
+		{"スート違い", []*Card{card(SuitSpade, 3), card(SuitHeart, 4), card(SuitSpade, 5)}, false},
+		{"2枚", []*Card{card(SuitSpade, 3), card(SuitSpade, 4)}, false},
+		{"同じランク", []*Card{card(SuitSpade, 3), card(SuitSpade, 3), card(SuitSpade, 4)}, false},
+		{"間をジョーカーで埋める", []*Card{card(SuitSpade, 3), joker(1), card(SuitSpade, 5)}, true},
+		{"ジョーカーが足りない", []*Card{card(SuitSpade, 3), joker(1), card(SuitSpade, 6)}, false},
+		{"端にジョーカー", []*Card{card(SuitSpade, 3), card(SuitSpade, 4), joker(1)}, true},
+		{"2の下にジョーカー", []*Card{joker(1), joker(2), card(SuitSpade, RankTwo)}, true},
+		{"ジョーカーのみ", []*Card{joker(1), joker(2), joker(3)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSequence(tt.cards); got != tt.want {
+				t.Errorf("IsSequence(%v) = %v, want %v", tt.cards, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateSequenceStrength(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards []*Card
+		isRev bool
+		want  int
+	}{
+		{"3-4-5", []*Card{card(SuitSpade, 3), card(SuitSpade, 4), card(SuitSpade, 5)}, false, 3},
+		{"K-A-2", []*Card{card(SuitSpade, RankKing), card(SuitSpade, RankAce), card(SuitSpade, RankTwo)}, false, 13},
+		{"ジョーカーは上の端に置く", []*Card{card(SuitSpade, 3), card(SuitSpade, 4), joker(1)}, false, 3},
+		{"2より上には置けない", []*Card{card(SuitSpade, RankAce), card(SuitSpade, RankTwo), joker(1)}, false, 13},
+		{"革命中の3-4-5", []*Card{card(SuitSpade, 3), card(SuitSpade, 4), card(SuitSpade, 5)}, true, 13},
+		{"革命中のK-A-2", []*Card{card(SuitSpade, RankKing), card(SuitSpade, RankAce), card(SuitSpade, RankTwo)}, true, 3},
+		{"革命中はジョーカーを下の端に置く", []*Card{card(SuitSpade, 4), card(SuitSpade, 5), joker(1)}, true, 13},
+		{"革命中も3より下には置けない", []*Card{card(SuitSpade, 3), card(SuitSpade, 4), joker(1)}, true, 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateSequenceStrength(tt.cards, tt.isRev); got != tt.want {
+				t.Errorf("calculateSequenceStrength(%v, %v) = %d, want %d", tt.cards, tt.isRev, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSequenceComparison(t *testing.T) {
+	low := []*Card{card(SuitHeart, 3), card(SuitHeart, 4), card(SuitHeart, 5)}
+	high := []*Card{card(SuitHeart, RankQueen), card(SuitHeart, RankKing), card(SuitHeart, RankAce)}
+
+	tests := []struct {
+		name       string
+		revolution bool
+		field      []*Card
+		play       []*Card
+		wantOK     bool
+	}{
+		{"通常は上の階段が強い", false, low, high, true},
+		{"通常は下の階段を出せない", false, high, low, false},
+		{"革命中は下の階段が強い", true, high, low, true},
+		{"革命中は上の階段を出せない", true, low, high, false},
+		{"11バック中は下の階段が強い", false,
+			[]*Card{card(SuitSpade, 9), card(SuitSpade, 10), card(SuitSpade, RankJack)}, low, true},
+		{"11バック中は上の階段を出せない", false,
+			[]*Card{card(SuitSpade, 9), card(SuitSpade, 10), card(SuitSpade, RankJack)}, high, false},
+		{"革命中の11バックは上の階段が強い", true,
+			[]*Card{card(SuitSpade, 9), card(SuitSpade, 10), card(SuitSpade, RankJack)}, high, true},
+		{"枚数が違う階段は出せない", false, low,
+			[]*Card{card(SuitHeart, 10), card(SuitHeart, RankJack), card(SuitHeart, RankQueen), card(SuitHeart, RankKing)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Game{Rules: RuleSet{ElevenBack: true}, IsRevolution: tt.revolution}
+			g.FieldCards = tt.field
+			g.LastHandType = HandTypeSequence
+
+			_, _, err := g.checkPlay(tt.play, g.EffectiveRevolution())
+			if ok := err == nil; ok != tt.wantOK {
+				t.Errorf("checkPlay(%v) on %v: err = %v, want ok = %v", tt.play, tt.field, err, tt.wantOK)
+			}
+		})
+	}
+}
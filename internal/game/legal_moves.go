@@ -0,0 +1,146 @@
+package game
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LegalMoves は現在の場に対して出せる手札の組み合わせをすべて返す
+// 自分の手番でない場合や、交換・効果の選択待ちの間は空になる
+func (g *Game) LegalMoves(userID int64) [][]*Card {
+	moves := [][]*Card{}
+	if g.IsFinished || g.IsExchanging() || g.PendingEffect != nil {
+		return moves
+	}
+	if len(g.Players) == 0 || g.Players[g.Turn].UserID != userID {
+		return moves
+	}
+
+	player := g.Players[g.Turn]
+	effectiveRev := g.EffectiveRevolution()
+
+	seen := make(map[string]bool)
+	for _, cards := range candidateMoves(player.Hand) {
+		key := moveKey(cards)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if _, _, err := g.checkPlay(cards, effectiveRev); err == nil {
+			moves = append(moves, cards)
+		}
+	}
+	return moves
+}
+
+// candidateMoves は手札から作れる役の候補（単騎・ペア・階段）を列挙する
+// ジョーカーは他のカードの代わりとして組み合わせる
+func candidateMoves(hand []*Card) [][]*Card {
+	var jokers []*Card
+	byRank := make(map[int][]*Card)
+	bySuit := make(map[Suit]map[int]*Card)
+	for _, c := range hand {
+		if c.Suit == SuitJoker {
+			jokers = append(jokers, c)
+			continue
+		}
+		r := toSeqRank(c)
+		byRank[r] = append(byRank[r], c)
+		if bySuit[c.Suit] == nil {
+			bySuit[c.Suit] = make(map[int]*Card)
+		}
+		bySuit[c.Suit][r] = c
+	}
+
+	var moves [][]*Card
+
+	// 単騎
+	for _, c := range hand {
+		moves = append(moves, []*Card{c})
+	}
+
+	// ペア（同じランクの組み合わせ＋ジョーカー）
+	ranks := make([]int, 0, len(byRank))
+	for r := range byRank {
+		ranks = append(ranks, r)
+	}
+	sort.Ints(ranks)
+	for _, r := range ranks {
+		cards := byRank[r]
+		for mask := 1; mask < 1<<len(cards); mask++ {
+			var subset []*Card
+			for i, c := range cards {
+				if mask&(1<<i) != 0 {
+					subset = append(subset, c)
+				}
+			}
+			for j := 0; j <= len(jokers); j++ {
+				if len(subset)+j < 2 {
+					continue
+				}
+				move := append(append([]*Card{}, subset...), jokers[:j]...)
+				moves = append(moves, move)
+			}
+		}
+	}
+	// ジョーカーのみのペア
+	for j := 2; j <= len(jokers); j++ {
+		moves = append(moves, append([]*Card{}, jokers[:j]...))
+	}
+
+	// 階段（足りないランクはジョーカーで埋める）
+	for s := SuitSpade; s <= SuitClub; s++ {
+		suitCards := bySuit[s]
+		if len(suitCards) == 0 {
+			continue
+		}
+		for lo := 1; lo <= maxSeqRank; lo++ {
+			for hi := lo + 2; hi <= maxSeqRank; hi++ {
+				moves = append(moves, sequencesInRange(suitCards, jokers, lo, hi)...)
+			}
+		}
+	}
+
+	return moves
+}
+
+// sequencesInRange は lo から hi までの階段を列挙する
+// 各ランクには手札のカードかジョーカーを当てる（持っているカードを残してジョーカーで代用する手も含める）
+func sequencesInRange(suitCards map[int]*Card, jokers []*Card, lo, hi int) [][]*Card {
+	var moves [][]*Card
+	var walk func(r int, move []*Card, usedJokers, normals int)
+	walk = func(r int, move []*Card, usedJokers, normals int) {
+		if r > hi {
+			if normals > 0 {
+				moves = append(moves, slices.Clone(move))
+			}
+			return
+		}
+		if c, ok := suitCards[r]; ok {
+			walk(r+1, append(move, c), usedJokers, normals+1)
+		}
+		if usedJokers < len(jokers) {
+			walk(r+1, append(move, jokers[usedJokers]), usedJokers+1, normals)
+		}
+	}
+	walk(lo, make([]*Card, 0, hi-lo+1), 0, 0)
+	return moves
+}
+
+// moveKey はカードの組み合わせを一意に表すキーを返す
+func moveKey(cards []*Card) string {
+	ids := make([]int, len(cards))
+	for i, c := range cards {
+		ids[i] = c.ID
+	}
+	sort.Ints(ids)
+
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// jokerFreeKey はジョーカーを区別せずに組み合わせを表すキーを返す
+// LegalMoves はどのジョーカーを使うかの違いを別の手として列挙しない
+func jokerFreeKey(cards []*Card) string {
+	var ids []int
+	jokers := 0
+	for _, c := range cards {
+		if c.Suit == SuitJoker {
+			jokers++
+			continue
+		}
+		ids = append(ids, c.ID)
+	}
+	sort.Ints(ids)
+	return fmt.Sprintf("%v+J%d", ids, jokers)
+}
+
+func TestLegalMovesMatchesCheckPlay(t *testing.T) {
+	hand := []*Card{
+		card(SuitSpade, 3), card(SuitHeart, 3), card(SuitHeart, 4), card(SuitHeart, 5),
+		card(SuitHeart, 6), card(SuitSpade, 7), card(SuitHeart, 7), card(SuitSpade, RankJack),
+		card(SuitSpade, RankTwo), joker(1), joker(2),
+	}
+	all := RuleSet{EightCut: true, ElevenBack: true, SpadeThree: true, Revolution: true, Shibari: true, JokerCount: 2}
+
+	tests := []struct {
+		name       string
+		rules      RuleSet
+		revolution bool
+		field      []*Card
+		fieldType  HandType
+		locked     []Suit
+	}{
+		{name: "場が空", rules: all},
+		{name: "単騎", rules: all, field: []*Card{card(SuitDiamond, 6)}, fieldType: HandTypeSingle},
+		{name: "革命中の単騎", rules: all, revolution: true, field: []*Card{card(SuitDiamond, 6)}, fieldType: HandTypeSingle},
+		{name: "ペア", rules: all, field: []*Card{card(SuitDiamond, 4), card(SuitClub, 4)}, fieldType: HandTypePair},
+		{name: "階段", rules: all, field: []*Card{card(SuitDiamond, 4), card(SuitDiamond, 5), card(SuitDiamond, 6)}, fieldType: HandTypeSequence},
+		{name: "革命中の階段", rules: all, revolution: true,
+			field: []*Card{card(SuitDiamond, 9), card(SuitDiamond, 10), card(SuitDiamond, RankQueen)}, fieldType: HandTypeSequence},
+		{name: "11バック", rules: all, field: []*Card{card(SuitDiamond, RankJack)}, fieldType: HandTypeSingle},
+		{name: "革命中の11バック", rules: all, revolution: true, field: []*Card{card(SuitDiamond, RankJack)}, fieldType: HandTypeSingle},
+		{name: "縛り", rules: all, field: []*Card{card(SuitDiamond, 3)}, fieldType: HandTypeSingle, locked: []Suit{SuitHeart}},
+		{name: "ペアの縛り", rules: all,
+			field: []*Card{card(SuitDiamond, 5), card(SuitClub, 5)}, fieldType: HandTypePair, locked: []Suit{SuitSpade, SuitHeart}},
+		{name: "ジョーカーにスペ3", rules: all, field: []*Card{joker(3)}, fieldType: HandTypeSingle},
+		{name: "スペ3返しなし", rules: RuleSet{JokerCount: 2}, field: []*Card{joker(3)}, fieldType: HandTypeSingle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t, tt.rules, slices.Clone(hand), []*Card{card(SuitClub, RankKing)})
+			g.IsRevolution = tt.revolution
+			g.FieldCards = tt.field
+			g.LastHandType = tt.fieldType
+			g.LockedSuits = tt.locked
+
+			moves := g.LegalMoves(1)
+
+			got := make(map[string]bool)
+			seen := make(map[string]bool)
+			for _, m := range moves {
+				if seen[moveKey(m)] {
+					t.Errorf("同じ手が2回含まれている: %v", m)
+				}
+				seen[moveKey(m)] = true
+
+				if _, _, err := g.checkPlay(m, g.EffectiveRevolution()); err != nil {
+					t.Errorf("出せない手が含まれている: %v (%v)", m, err)
+				}
+				got[jokerFreeKey(m)] = true
+			}
+
+			// 手札の全ての組み合わせについて、出せるものが漏れなく含まれているか
+			for mask := 1; mask < 1<<len(hand); mask++ {
+				var subset []*Card
+				for i, c := range hand {
+					if mask&(1<<i) != 0 {
+						subset = append(subset, c)
+					}
+				}
+				if _, _, err := g.checkPlay(subset, g.EffectiveRevolution()); err != nil {
+					continue
+				}
+				if !got[jokerFreeKey(subset)] {
+					t.Errorf("出せる手が含まれていない: %v", subset)
+				}
+			}
+		})
+	}
+}
+
+func TestLegalMovesOnlyForCurrentTurn(t *testing.T) {
+	g := newTestGame(t, DefaultRuleSet(),
+		[]*Card{card(SuitSpade, 3), card(SuitSpade, 4)},
+		[]*Card{card(SuitHeart, 5), card(SuitHeart, 6)},
+	)
+
+	if len(g.LegalMoves(1)) == 0 {
+		t.Fatal("手番のプレイヤーに出せる手がない")
+	}
+	if moves := g.LegalMoves(2); len(moves) != 0 {
+		t.Fatalf("手番でないプレイヤーに出せる手がある: %v", moves)
+	}
+
+	g.PendingEffect = &PendingEffect{Type: EffectSevenPass, UserID: 1, Count: 1}
+	if moves := g.LegalMoves(1); len(moves) != 0 {
+		t.Fatalf("効果の選択待ちに出せる手がある: %v", moves)
+	}
+}
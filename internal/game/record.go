@@ -0,0 +1,186 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ActionType はプレイヤーの行動の種類
+type ActionType string
+
+const (
+	ActionPlay          ActionType = "play"           // カードを出す
+	ActionPass          ActionType = "pass"           // パス
+	ActionResolveEffect ActionType = "resolve_effect" // 7渡し・10捨てのカード選択
+	ActionExchange      ActionType = "exchange"       // カード交換で返す
+	ActionAutoExchange  ActionType = "auto_exchange"  // 期限切れによる自動交換
+	ActionLeave         ActionType = "leave"          // 途中退出
+)
+
+// Action は記録された1回の行動
+type Action struct {
+	Type    ActionType `json:"type"`
+	UserID  int64      `json:"user_id,omitempty"`
+	CardIDs []int      `json:"card_ids,omitempty"`
+}
+
+// RecordSeat は開始時の席の状態
+type RecordSeat struct {
+	UserID int64 `json:"user_id"`
+	// 開始時の順位（前回の順位。カード交換に使う）
+	Rank int `json:"rank"`
+	// 配られた手札（交換前）
+	Hand []*Card `json:"hand"`
+}
+
+// Record は1ゲーム分の記録
+// 初期状態から行動を順に適用すると、任意の時点の状態を再現できる
+type Record struct {
+	Rules RuleSet `json:"rules"`
+	// シャッフルに使った乱数の種とそのハッシュ（種から配り直して検証できる）
+	Seed     string        `json:"seed,omitempty"`
+	SeedHash string        `json:"seed_hash,omitempty"`
+	Seats    []*RecordSeat `json:"seats"`
+	Actions  []*Action     `json:"actions"`
+}
+
+func newRecord(players []*Player, rules RuleSet, hands [][]*Card) *Record {
+	seats := make([]*RecordSeat, len(players))
+	for i, p := range players {
+		hand := make([]*Card, len(hands[i]))
+		copy(hand, hands[i])
+		seats[i] = &RecordSeat{UserID: p.UserID, Rank: p.Rank, Hand: hand}
+	}
+	return &Record{Rules: rules, Seats: seats, Actions: []*Action{}}
+}
+
+// record は行動を記録する
+func (g *Game) record(actionType ActionType, userID int64, cards []*Card) {
+	if g.Record == nil {
+		return
+	}
+
+	var ids []int
+	for _, c := range cards {
+		ids = append(ids, c.ID)
+	}
+	g.Record.Actions = append(g.Record.Actions, &Action{Type: actionType, UserID: userID, CardIDs: ids})
+}
+
+// Replay は記録から step 手目まで進めた状態のゲームを作る（0なら開始直後）
+func Replay(rec *Record, step int) (*Game, error) {
+	if rec == nil {
+		return nil, errors.New("記録がありません")
+	}
+	if step < 0 || step > len(rec.Actions) {
+		return nil, fmt.Errorf("手数は0〜%dで指定してください", len(rec.Actions))
+	}
+
+	players := make([]*Player, len(rec.Seats))
+	hands := make([][]*Card, len(rec.Seats))
+	for i, seat := range rec.Seats {
+		players[i] = newPlayer(seat.UserID, seat.Rank)
+		hands[i] = make([]*Card, len(seat.Hand))
+		copy(hands[i], seat.Hand)
+	}
+
+	g := &Game{Players: players, IsReplay: true, RevealHands: true, Seed: rec.Seed, SeedHash: rec.SeedHash}
+	g.start(rec.Rules, hands)
+	g.Record.Seed = rec.Seed
+	g.Record.SeedHash = rec.SeedHash
+
+	for i, action := range rec.Actions[:step] {
+		if err := g.apply(action); err != nil {
+			return nil, fmt.Errorf("%d手目を再現できません: %w", i+1, err)
+		}
+	}
+	return g, nil
+}
+
+// DelayedView は delay 手前の状態を全員の手札を公開した形で返す（観戦者向け）
+// 乱数の種は終了まで伏せたままにする
+func (g *Game) DelayedView(delay int) (*Game, error) {
+	if g.Record == nil {
+		return nil, errors.New("記録がありません")
+	}
+	step := len(g.Record.Actions) - delay
+	if step < 0 {
+		step = 0
+	}
+
+	v, err := Replay(g.Record, step)
+	if err != nil {
+		return nil, err
+	}
+	v.IsReplay = false
+	v.Seed = ""
+	v.Record = nil
+
+	// 通し番号を実際のゲームに揃え、遅延した出来事も game_update として配信できるようにする
+	if len(g.Events) > 0 {
+		base := g.Events[0].Seq - 1
+		for _, ev := range v.Events {
+			ev.Seq += base
+		}
+		v.LastEventSeq += base
+	}
+	return v, nil
+}
+
+// apply は記録された行動を適用する
+func (g *Game) apply(action *Action) error {
+	switch action.Type {
+	case ActionPlay:
+		cards, err := g.findCards(action.UserID, action.CardIDs)
+		if err != nil {
+			return err
+		}
+		return g.Play(action.UserID, cards)
+	case ActionPass:
+		return g.Pass(action.UserID)
+	case ActionResolveEffect:
+		cards, err := g.findCards(action.UserID, action.CardIDs)
+		if err != nil {
+			return err
+		}
+		return g.ResolveEffect(action.UserID, cards)
+	case ActionExchange:
+		cards, err := g.findCards(action.UserID, action.CardIDs)
+		if err != nil {
+			return err
+		}
+		return g.SubmitExchange(action.UserID, cards)
+	case ActionAutoExchange:
+		g.AutoExchange()
+		return nil
+	case ActionLeave:
+		g.RemovePlayer(action.UserID)
+		return nil
+	default:
+		return fmt.Errorf("不明な行動です: %s", action.Type)
+	}
+}
+
+// findCards はプレイヤーの手札からIDでカードを探す
+func (g *Game) findCards(userID int64, cardIDs []int) ([]*Card, error) {
+	i := g.playerIndex(userID)
+	if i < 0 {
+		return nil, errors.New("プレイヤーが見つかりません")
+	}
+
+	cards := make([]*Card, 0, len(cardIDs))
+	for _, id := range cardIDs {
+		found := false
+		for _, c := range g.Players[i].Hand {
+			if c.ID == id {
+				cards = append(cards, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("カード %d が手札にありません", id)
+		}
+	}
+	return cards, nil
+}
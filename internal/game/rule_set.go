@@ -0,0 +1,43 @@
+package game
+
+import "fmt"
+
+// RuleSet はローカルルールの有効/無効を表す
+type RuleSet struct {
+	EightCut        bool `json:"eight_cut"`        // 8切り
+	ElevenBack      bool `json:"eleven_back"`      // 11バック
+	SpadeThree      bool `json:"spade_three"`      // スペ3返し
+	Revolution      bool `json:"revolution"`       // 革命（4枚以上）
+	MiyakoOchi      bool `json:"miyako_ochi"`      // 都落ち
+	Shibari         bool `json:"shibari"`          // 縛り
+	SevenPass       bool `json:"seven_pass"`       // 7渡し
+	TenDiscard      bool `json:"ten_discard"`      // 10捨て
+	NineReverse     bool `json:"nine_reverse"`     // 9リバース
+	FiveSkip        bool `json:"five_skip"`        // 5スキップ
+	ForbiddenFinish bool `json:"forbidden_finish"` // 禁止あがり
+	JokerCount      int  `json:"joker_count"`      // ジョーカーの枚数
+	TurnTimeLimit   int  `json:"turn_time_limit"`  // 1手の制限時間（秒、0なら無制限）
+}
+
+// DefaultRuleSet は従来の固定ルールと同じ設定を返す
+func DefaultRuleSet() RuleSet {
+	return RuleSet{
+		EightCut:   true,
+		ElevenBack: true,
+		SpadeThree: true,
+		Revolution: true,
+		MiyakoOchi: true,
+		JokerCount: 2,
+	}
+}
+
+// Validate はルール設定の整合性をチェックする
+func (r RuleSet) Validate() error {
+	if r.JokerCount < 0 || r.JokerCount > 2 {
+		return fmt.Errorf("ジョーカーの枚数は0〜2枚で指定してください")
+	}
+	if r.TurnTimeLimit < 0 {
+		return fmt.Errorf("制限時間は0秒以上で指定してください")
+	}
+	return nil
+}
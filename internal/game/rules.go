@@ -3,6 +3,7 @@ package game
 import (
 	"errors"
 	"fmt"
+	"sort"
 )
 
 type HandType int
@@ -41,18 +42,20 @@ func AnalyzeHand(cards []*Card, isRev bool) (HandType, int, error) {
 	return HandTypeInvalid, 0, errors.New("役として成立していません")
 }
 
-func ValidatePlay(fieldCards []*Card, fieldType HandType, fieldStrength int, playCards []*Card, playType HandType, playStrength int) error {
+func ValidatePlay(fieldCards []*Card, fieldType HandType, fieldStrength int, playCards []*Card, playType HandType, playStrength int, lockedSuits []Suit, rules RuleSet) error {
 	// 場に何もないならOK
 	if len(fieldCards) == 0 {
 		return nil
 	}
 
+	// 縛り
+	if len(lockedSuits) > 0 && !matchesLock(lockedSuits, playCards) {
+		return fmt.Errorf("縛りのスートと違います")
+	}
+
 	// スペ3返し
-	// fieldStrengthが最強かつ単騎の場合
-	if fieldType == HandTypeSingle && len(fieldCards) == 1 && fieldCards[0].Suit == SuitJoker {
-		if len(playCards) == 1 && playCards[0].Suit == SuitSpade && playCards[0].Rank == RankThree {
-			return nil // スペ3返し成功
-		}
+	if isSpadeThreeReturn(fieldCards, playCards, rules) {
+		return nil // スペ3返し成功
 	}
 
 	// 役の種類一致
@@ -101,15 +104,119 @@ func calculateGroupStrength(cards []*Card, isRev bool) int {
 	return 99 // All Jokers
 }
 
+// calculateSequenceStrength は階段の強さを返す
+// 枚数が同じ階段同士でのみ比較されるため、通常時は最上段、革命時は最下段で決まる
+// 余ったジョーカーは強くなる側の端に置く
 func calculateSequenceStrength(cards []*Card, isRev bool) int {
-	maxStr := -1
+	lo, hi := seqRankRange(cards)
+	n := len(cards)
+
+	if isRev {
+		bottom := hi - n + 1
+		if bottom < 1 {
+			bottom = 1
+		}
+		return maxSeqRank + 1 - bottom
+	}
+
+	top := lo + n - 1
+	if top > maxSeqRank {
+		top = maxSeqRank
+	}
+	return top
+}
+
+// isSpadeThreeReturn は単騎のジョーカーにスペードの3を出したか判定する
+func isSpadeThreeReturn(fieldCards, playCards []*Card, rules RuleSet) bool {
+	if !rules.SpadeThree || len(fieldCards) != 1 || fieldCards[0].Suit != SuitJoker {
+		return false
+	}
+	return len(playCards) == 1 && playCards[0].Suit == SuitSpade && playCards[0].Rank == RankThree
+}
+
+// suitsOf はジョーカー以外のスートを昇順で返す
+func suitsOf(cards []*Card) ([]Suit, int) {
+	suits := make([]Suit, 0, len(cards))
+	jokers := 0
 	for _, c := range cards {
-		if c.Suit != SuitJoker {
-			s := GetStrength(c, isRev)
-			if s > maxStr {
-				maxStr = s
-			}
+		if c.Suit == SuitJoker {
+			jokers++
+			continue
+		}
+		suits = append(suits, c.Suit)
+	}
+	sort.Slice(suits, func(i, j int) bool {
+		return suits[i] < suits[j]
+	})
+	return suits, jokers
+}
+
+// lockSuits は連続した2回の出し札から縛るスートを求める
+// ジョーカーを含む出し札では縛りは発生しない
+func lockSuits(prevCards, playCards []*Card) []Suit {
+	prevSuits, prevJokers := suitsOf(prevCards)
+	playSuits, playJokers := suitsOf(playCards)
+	if prevJokers > 0 || playJokers > 0 {
+		return nil
+	}
+	if len(prevSuits) == 0 || len(prevSuits) != len(playSuits) {
+		return nil
+	}
+	for i := range prevSuits {
+		if prevSuits[i] != playSuits[i] {
+			return nil
+		}
+	}
+	return playSuits
+}
+
+// matchesLock は出し札が縛りのスートに従っているか判定する
+// ジョーカーは縛られたどのスートの代わりにもなる
+func matchesLock(lockedSuits []Suit, playCards []*Card) bool {
+	remaining := make(map[Suit]int, len(lockedSuits))
+	for _, s := range lockedSuits {
+		remaining[s]++
+	}
+	playSuits, _ := suitsOf(playCards)
+	for _, s := range playSuits {
+		if remaining[s] == 0 {
+			return false
+		}
+		remaining[s]--
+	}
+	return true
+}
+
+// countRank は指定したランクのカードの枚数を返す
+func countRank(cards []*Card, rank Rank) int {
+	n := 0
+	for _, c := range cards {
+		if c.Suit != SuitJoker && c.Rank == rank {
+			n++
+		}
+	}
+	return n
+}
+
+// forbiddenFinishReason は出したカードであがると反則になる場合に理由を返す
+// 反則でなければ空文字を返す
+func forbiddenFinishReason(cards []*Card, isRev bool, rules RuleSet) string {
+	strongest := Rank(RankTwo)
+	if isRev {
+		strongest = RankThree
+	}
+
+	for _, c := range cards {
+		switch {
+		case c.Suit == SuitJoker:
+			return "反則あがり（ジョーカー）"
+		case rules.EightCut && c.Rank == RankEight:
+			return "反則あがり（8）"
+		case rules.SpadeThree && c.Suit == SuitSpade && c.Rank == RankThree:
+			return "反則あがり（スペードの3）"
+		case c.Rank == strongest:
+			return fmt.Sprintf("反則あがり（%d）", strongest)
 		}
 	}
-	return maxStr
+	return ""
 }
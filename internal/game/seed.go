@@ -0,0 +1,73 @@
+package game
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Seed はシャッフルに使う乱数の種
+// ゲーム開始時にハッシュだけを公開し、終了後に種を公開することで
+// 配られた手札が事前に決まっていたことを誰でも確認できる
+type Seed [32]byte
+
+// NewSeed は暗号論的乱数から種を作る
+func NewSeed() Seed {
+	var s Seed
+	// crypto/rand.Read はエラーを返さない
+	_, _ = rand.Read(s[:])
+	return s
+}
+
+// ParseSeed は16進数表記の種を読み込む
+func ParseSeed(s string) (Seed, error) {
+	var seed Seed
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(seed) {
+		return seed, errors.New("乱数の種の形式が不正です")
+	}
+	copy(seed[:], b)
+	return seed, nil
+}
+
+// String は種を16進数で返す
+func (s Seed) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// Hash は公開用のハッシュ（SHA-256 の16進数）を返す
+func (s Seed) Hash() string {
+	sum := sha256.Sum256(s[:])
+	return hex.EncodeToString(sum[:])
+}
+
+// DealFromSeed は種から山札を作ってシャッフルし、配った手札を返す
+func DealFromSeed(seed Seed, jokerCount, numPlayers int) [][]*Card {
+	deck := NewDeck(jokerCount)
+	deck.ShuffleWithSeed(seed)
+	return deck.Deal(numPlayers)
+}
+
+// VerifyDeal は記録に残った種がハッシュと一致し、種から同じ手札が配られることを確認する
+func VerifyDeal(rec *Record) error {
+	if rec == nil || rec.Seed == "" {
+		return errors.New("乱数の種が記録されていません")
+	}
+	seed, err := ParseSeed(rec.Seed)
+	if err != nil {
+		return err
+	}
+	if seed.Hash() != rec.SeedHash {
+		return errors.New("乱数の種が公開されたハッシュと一致しません")
+	}
+
+	hands := DealFromSeed(seed, rec.Rules.JokerCount, len(rec.Seats))
+	for i, seat := range rec.Seats {
+		if moveKey(hands[i]) != moveKey(seat.Hand) {
+			return fmt.Errorf("ユーザー %d の手札が種から配られる手札と一致しません", seat.UserID)
+		}
+	}
+	return nil
+}
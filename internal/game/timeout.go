@@ -0,0 +1,102 @@
+package game
+
+import "errors"
+
+// ForceTimeout は制限時間が切れたプレイヤーの代わりに行動する
+// 交換・効果の選択は弱いカードを自動で選び、手番は場が空なら一番弱いカードを出して、それ以外はパスする
+// 代わりに行動したプレイヤーのIDを返す
+func (g *Game) ForceTimeout() []int64 {
+	if g.IsFinished || len(g.Players) == 0 {
+		return nil
+	}
+
+	// カード交換
+	if g.IsExchanging() {
+		ids := make([]int64, len(g.PendingExchanges))
+		for i, ex := range g.PendingExchanges {
+			ids[i] = ex.FromID
+		}
+		g.AutoExchange()
+		return ids
+	}
+
+	// 7渡し・10捨て
+	if effect := g.PendingEffect; effect != nil {
+		if err := g.ResolveEffect(effect.UserID, g.weakestCards(effect.UserID, effect.Count)); err != nil {
+			return nil
+		}
+		return []int64{effect.UserID}
+	}
+
+	// 手番のプレイヤー
+	player := g.Players[g.Turn]
+	if err := g.forceTurn(player.UserID); err != nil {
+		return nil
+	}
+	return []int64{player.UserID}
+}
+
+// ForceAction は指定したプレイヤーの入力待ちだけを代わりに進める（切断したプレイヤーなど）
+// ForceTimeout と同じく弱いカードを選ぶか出すか、パスする。入力待ちでなければ false を返す
+func (g *Game) ForceAction(userID int64) bool {
+	if g.IsFinished || len(g.Players) == 0 {
+		return false
+	}
+
+	// カード交換（他のプレイヤーの返却は待つ）
+	if g.IsExchanging() {
+		for _, ex := range g.PendingExchanges {
+			if ex.FromID == userID {
+				return g.SubmitExchange(userID, g.weakestCards(userID, ex.Count)) == nil
+			}
+		}
+		return false
+	}
+
+	// 7渡し・10捨て
+	if effect := g.PendingEffect; effect != nil {
+		if effect.UserID != userID {
+			return false
+		}
+		return g.ResolveEffect(userID, g.weakestCards(userID, effect.Count)) == nil
+	}
+
+	// 手番
+	if g.Players[g.Turn].UserID != userID {
+		return false
+	}
+	return g.forceTurn(userID) == nil
+}
+
+// forceTurn は手番のプレイヤーの代わりに、場が空なら一番弱いカードを1枚出し、それ以外はパスする
+func (g *Game) forceTurn(userID int64) error {
+	if len(g.FieldCards) > 0 {
+		return g.Pass(userID)
+	}
+
+	player := g.Players[g.playerIndex(userID)]
+	if len(player.Hand) == 0 {
+		return errors.New("手札がありません")
+	}
+	isRev := g.EffectiveRevolution()
+	weakest := player.Hand[0]
+	for _, c := range player.Hand[1:] {
+		if GetStrength(c, isRev) < GetStrength(weakest, isRev) {
+			weakest = c
+		}
+	}
+	return g.Play(userID, []*Card{weakest})
+}
+
+// weakestCards は手札の並びを変えずに、弱い順に count 枚を返す
+func (g *Game) weakestCards(userID int64, count int) []*Card {
+	player := g.Players[g.playerIndex(userID)]
+	hand := make([]*Card, len(player.Hand))
+	copy(hand, player.Hand)
+	sortHandForExchange(hand)
+
+	if count > len(hand) {
+		count = len(hand)
+	}
+	return hand[:count]
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+// newLeadAgainGame は1人目が出して残り全員がパスし、1人目に親が戻った4人のゲームを作る
+func newLeadAgainGame(t *testing.T) *Game {
+	t.Helper()
+	g := newTestGame(t, RuleSet{},
+		[]*Card{card(SuitSpade, 6), card(SuitSpade, 9), card(SuitSpade, 5)},
+		[]*Card{card(SuitHeart, 3), card(SuitHeart, 4)},
+		[]*Card{card(SuitDiamond, 3), card(SuitDiamond, 4)},
+		[]*Card{card(SuitClub, 3), card(SuitClub, 4)},
+	)
+	if err := g.Play(1, []*Card{g.Players[0].Hand[0]}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int64{2, 3, 4} {
+		if err := g.Pass(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(g.FieldCards) != 0 || g.CurrentPlayerID() != 1 {
+		t.Fatalf("場が流れていない: 場 %v, 手番 %d", g.FieldCards, g.CurrentPlayerID())
+	}
+	return g
+}
+
+func TestPassRejectedOnEmptyTable(t *testing.T) {
+	g := newLeadAgainGame(t)
+	if err := g.Pass(1); err == nil {
+		t.Fatal("場が空なのに親がパスできてしまう")
+	}
+}
+
+func TestTimeoutLeadsWithWeakestCard(t *testing.T) {
+	tests := []struct {
+		name  string
+		force func(g *Game) bool
+	}{
+		{"ForceTimeout", func(g *Game) bool { return slices.Equal(g.ForceTimeout(), []int64{1}) }},
+		{"ForceAction", func(g *Game) bool { return g.ForceAction(1) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newLeadAgainGame(t)
+			if !tt.force(g) {
+				t.Fatal("親の代わりに行動していない")
+			}
+
+			// 一番弱いカードを出して、次の人に手番が回る（親に戻らない）
+			want := card(SuitSpade, 5).ID
+			if len(g.FieldCards) != 1 || g.FieldCards[0].ID != want {
+				t.Fatalf("場 = %v, want [%d]", g.FieldCards, want)
+			}
+			if got := g.CurrentPlayerID(); got != 2 {
+				t.Fatalf("手番 = %d, want 2", got)
+			}
+
+			// 残りの全員がパスすると親に戻る
+			for _, id := range []int64{2, 3, 4} {
+				if err := g.Pass(id); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := g.CurrentPlayerID(); got != 1 {
+				t.Fatalf("場が流れた後の手番 = %d, want 1", got)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -8,6 +11,12 @@ import (
 	"github.com/ne241099/daifugo-server/repository"
 )
 
+var (
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrSessionExpired = errors.New("session expired")
+)
+
 type AuthMiddleware struct {
 	authenticator auth.Authenticator
 	userRepo      repository.UserRepository
@@ -38,19 +47,15 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 		}
 		token := parts[1]
 
-		uid, tokenVer, err := m.authenticator.VerifyToken(r.Context(), token)
-		if err != nil {
+		uid, err := m.VerifyToken(r.Context(), token)
+		switch {
+		case errors.Is(err, ErrInvalidToken):
 			next.ServeHTTP(w, r)
 			return
-		}
-
-		user, err := m.userRepo.GetUser(r.Context(), uid)
-		if err != nil {
+		case errors.Is(err, ErrUserNotFound):
 			http.Error(w, "User not found", http.StatusUnauthorized)
 			return
-		}
-
-		if user.TokenVersion != tokenVer {
+		case errors.Is(err, ErrSessionExpired):
 			http.Error(w, "Session expired (Logged in on another device)", http.StatusUnauthorized)
 			return
 		}
@@ -60,3 +65,23 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// VerifyToken はトークンを検証し、最新のログインで発行されたものならユーザーIDを返す
+// HTTP 以外（WebSocket の接続時など）の認証にも使う
+func (m *AuthMiddleware) VerifyToken(ctx context.Context, token string) (int64, error) {
+	uid, tokenVer, err := m.authenticator.VerifyToken(ctx, token)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	user, err := m.userRepo.GetUser(ctx, uid)
+	if err != nil {
+		return 0, ErrUserNotFound
+	}
+
+	// 別の端末でログインし直した場合は無効
+	if user.TokenVersion != tokenVer {
+		return 0, ErrSessionExpired
+	}
+	return uid, nil
+}
@@ -0,0 +1,440 @@
+// Package notation はゲームの記録を人が読めるテキスト形式（棋譜）で読み書きする
+//
+// 形式の例:
+//
+//	[Format "daifugo/1"]
+//	[RoomID "3"]
+//	[FinishedAt "2026-01-02T15:04:05Z"]
+//	[Rules "eight_cut eleven_back spade_three revolution miyako_ochi"]
+//	[JokerCount "2"]
+//	[TurnTimeLimit "0"]
+//	[SeedHash "9f86d0..."]
+//	[Seed "a3c1e8..."]
+//
+//	seat 1 rank 0: ♠3 ♥10 ♦J Joker1
+//	seat -1 rank 0: ♣A ♠2 Joker2
+//
+//	1. 1 play ♠3
+//	2. -1 pass
+//	3. auto_exchange
+//
+// タグ、席（ユーザーID・開始時の順位・配られた手札）、行動の順に並べる
+// カードは Card.String() の表記で、ジョーカーだけは区別するため番号を付ける
+// 空行と # で始まる行は読み飛ばす
+package notation
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/model"
+)
+
+// FormatVersion は Format タグの値
+const FormatVersion = "daifugo/1"
+
+// ruleFlags はルール名（JSONと同じ表記）と設定値の対応
+func ruleFlags(r *game.RuleSet) []struct {
+	name  string
+	value *bool
+} {
+	return []struct {
+		name  string
+		value *bool
+	}{
+		{"eight_cut", &r.EightCut},
+		{"eleven_back", &r.ElevenBack},
+		{"spade_three", &r.SpadeThree},
+		{"revolution", &r.Revolution},
+		{"miyako_ochi", &r.MiyakoOchi},
+		{"shibari", &r.Shibari},
+		{"seven_pass", &r.SevenPass},
+		{"ten_discard", &r.TenDiscard},
+		{"nine_reverse", &r.NineReverse},
+		{"five_skip", &r.FiveSkip},
+		{"forbidden_finish", &r.ForbiddenFinish},
+	}
+}
+
+// cardToken はカードの表記を返す
+func cardToken(c *game.Card) string {
+	if c.Suit == game.SuitJoker {
+		// デッキの並びでジョーカーは最後に来る
+		return fmt.Sprintf("Joker%d", c.ID-52)
+	}
+	return c.String()
+}
+
+func cardTokens(cards []*game.Card) string {
+	tokens := make([]string, len(cards))
+	for i, c := range cards {
+		tokens[i] = cardToken(c)
+	}
+	return strings.Join(tokens, " ")
+}
+
+// Encode はゲームの記録を棋譜に変換する
+func Encode(rec *model.GameRecord) ([]byte, error) {
+	if rec == nil || rec.Record == nil {
+		return nil, fmt.Errorf("記録がありません")
+	}
+	r := rec.Record
+
+	var buf bytes.Buffer
+
+	// タグ
+	rules := r.Rules
+	var enabled []string
+	for _, f := range ruleFlags(&rules) {
+		if *f.value {
+			enabled = append(enabled, f.name)
+		}
+	}
+	fmt.Fprintf(&buf, "[Format %q]\n", FormatVersion)
+	fmt.Fprintf(&buf, "[RoomID \"%d\"]\n", rec.RoomID)
+	fmt.Fprintf(&buf, "[FinishedAt %q]\n", rec.FinishedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&buf, "[Rules %q]\n", strings.Join(enabled, " "))
+	fmt.Fprintf(&buf, "[JokerCount \"%d\"]\n", rules.JokerCount)
+	fmt.Fprintf(&buf, "[TurnTimeLimit \"%d\"]\n", rules.TurnTimeLimit)
+	if r.SeedHash != "" {
+		fmt.Fprintf(&buf, "[SeedHash %q]\n", r.SeedHash)
+	}
+	if r.Seed != "" {
+		fmt.Fprintf(&buf, "[Seed %q]\n", r.Seed)
+	}
+	buf.WriteString("\n")
+
+	// 席と配られた手札
+	for _, seat := range r.Seats {
+		fmt.Fprintf(&buf, "seat %d rank %d: %s\n", seat.UserID, seat.Rank, cardTokens(seat.Hand))
+	}
+	buf.WriteString("\n")
+
+	// 行動
+	for i, a := range r.Actions {
+		fmt.Fprintf(&buf, "%d.", i+1)
+		if a.Type != game.ActionAutoExchange {
+			fmt.Fprintf(&buf, " %d", a.UserID)
+		}
+		fmt.Fprintf(&buf, " %s", a.Type)
+		for _, id := range a.CardIDs {
+			c := cardByID(rules.JokerCount, id)
+			if c == nil {
+				return nil, fmt.Errorf("%d手目: 不明なカード %d", i+1, id)
+			}
+			fmt.Fprintf(&buf, " %s", cardToken(c))
+		}
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func cardByID(jokerCount, id int) *game.Card {
+	for _, c := range game.NewDeck(jokerCount) {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+var (
+	tagPattern    = regexp.MustCompile(`^\[([A-Za-z]+) "([^"]*)"\]$`)
+	seatPattern   = regexp.MustCompile(`^seat (-?\d+) rank (\d+):((?: \S+)*)$`)
+	actionPattern = regexp.MustCompile(`^(\d+)\.(?: (-?\d+))? ([a-z_]+)((?: \S+)*)$`)
+)
+
+// parser は1行ずつ読み進める状態
+type parser struct {
+	rec  *model.GameRecord
+	tags map[string]bool
+	// 表記からカードへの対応（JokerCount のタグを読んだ後に作る）
+	deck map[string]*game.Card
+	// 配られたカード（重複チェック用）
+	dealt map[int]bool
+	// 0: タグ, 1: 席, 2: 行動
+	section int
+}
+
+// Decode は棋譜を読み込んでゲームの記録に変換する
+// 書式の誤りに加えて、全ての行動を再生して反則がないことを確認する
+func Decode(data []byte) (*model.GameRecord, error) {
+	p := &parser{
+		rec: &model.GameRecord{
+			Record: &game.Record{Seats: []*game.RecordSeat{}, Actions: []*game.Action{}},
+		},
+		tags:  make(map[string]bool),
+		dealt: make(map[int]bool),
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := p.parseLine(line); err != nil {
+			return nil, fmt.Errorf("%d行目: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := p.finish(); err != nil {
+		return nil, err
+	}
+	return p.rec, nil
+}
+
+func (p *parser) parseLine(line string) error {
+	switch {
+	case strings.HasPrefix(line, "["):
+		if p.section > 0 {
+			return fmt.Errorf("タグは席より前に書いてください")
+		}
+		return p.parseTag(line)
+	case strings.HasPrefix(line, "seat "):
+		if p.section > 1 {
+			return fmt.Errorf("席は行動より前に書いてください")
+		}
+		if err := p.checkTags(); err != nil {
+			return err
+		}
+		p.section = 1
+		return p.parseSeat(line)
+	default:
+		if p.section < 1 {
+			return fmt.Errorf("行動の前に席を書いてください")
+		}
+		p.section = 2
+		return p.parseAction(line)
+	}
+}
+
+func (p *parser) parseTag(line string) error {
+	m := tagPattern.FindStringSubmatch(line)
+	if m == nil {
+		return fmt.Errorf("タグの書式が不正です: %s", line)
+	}
+	name, value := m[1], m[2]
+	if p.tags[name] {
+		return fmt.Errorf("タグ %s が重複しています", name)
+	}
+	p.tags[name] = true
+
+	rules := &p.rec.Record.Rules
+	switch name {
+	case "Format":
+		if value != FormatVersion {
+			return fmt.Errorf("対応していない形式です: %s", value)
+		}
+	case "RoomID":
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("RoomID が不正です: %s", value)
+		}
+		p.rec.RoomID = id
+	case "FinishedAt":
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("FinishedAt が不正です: %s", value)
+		}
+		p.rec.FinishedAt = t
+	case "Rules":
+		flags := ruleFlags(rules)
+		for _, rn := range strings.Fields(value) {
+			found := false
+			for _, f := range flags {
+				if f.name == rn {
+					if *f.value {
+						return fmt.Errorf("ルール %s が重複しています", rn)
+					}
+					*f.value = true
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("不明なルールです: %s", rn)
+			}
+		}
+	case "JokerCount":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("JokerCount が不正です: %s", value)
+		}
+		rules.JokerCount = n
+	case "TurnTimeLimit":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("TurnTimeLimit が不正です: %s", value)
+		}
+		rules.TurnTimeLimit = n
+	case "SeedHash":
+		p.rec.Record.SeedHash = value
+	case "Seed":
+		p.rec.Record.Seed = value
+	default:
+		return fmt.Errorf("不明なタグです: %s", name)
+	}
+	return nil
+}
+
+// checkTags は必須のタグが揃っているか確認し、カードの対応表を作る
+func (p *parser) checkTags() error {
+	if p.deck != nil {
+		return nil
+	}
+	for _, name := range []string{"Format", "Rules", "JokerCount"} {
+		if !p.tags[name] {
+			return fmt.Errorf("タグ %s がありません", name)
+		}
+	}
+	if err := p.rec.Record.Rules.Validate(); err != nil {
+		return err
+	}
+
+	p.deck = make(map[string]*game.Card)
+	for _, c := range game.NewDeck(p.rec.Record.Rules.JokerCount) {
+		p.deck[cardToken(c)] = c
+	}
+	return nil
+}
+
+func (p *parser) parseCards(s string) ([]*game.Card, error) {
+	fields := strings.Fields(s)
+	cards := make([]*game.Card, 0, len(fields))
+	for _, token := range fields {
+		c, ok := p.deck[token]
+		if !ok {
+			return nil, fmt.Errorf("不明なカードです: %s", token)
+		}
+		cards = append(cards, c)
+	}
+	return cards, nil
+}
+
+func (p *parser) parseSeat(line string) error {
+	m := seatPattern.FindStringSubmatch(line)
+	if m == nil {
+		return fmt.Errorf("席の書式が不正です: %s", line)
+	}
+	userID, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil || userID == 0 {
+		return fmt.Errorf("ユーザーIDが不正です: %s", m[1])
+	}
+	rank, _ := strconv.Atoi(m[2])
+
+	for _, seat := range p.rec.Record.Seats {
+		if seat.UserID == userID {
+			return fmt.Errorf("ユーザー %d の席が重複しています", userID)
+		}
+		if rank > 0 && seat.Rank == rank {
+			return fmt.Errorf("順位 %d が重複しています", rank)
+		}
+	}
+
+	hand, err := p.parseCards(m[3])
+	if err != nil {
+		return err
+	}
+	for _, c := range hand {
+		if p.dealt[c.ID] {
+			return fmt.Errorf("カード %s が重複しています", cardToken(c))
+		}
+		p.dealt[c.ID] = true
+	}
+
+	p.rec.Record.Seats = append(p.rec.Record.Seats, &game.RecordSeat{UserID: userID, Rank: rank, Hand: hand})
+	return nil
+}
+
+func (p *parser) parseAction(line string) error {
+	m := actionPattern.FindStringSubmatch(line)
+	if m == nil {
+		return fmt.Errorf("行動の書式が不正です: %s", line)
+	}
+	actions := p.rec.Record.Actions
+
+	step, _ := strconv.Atoi(m[1])
+	if step != len(actions)+1 {
+		return fmt.Errorf("%d手目が来るべき位置です", len(actions)+1)
+	}
+
+	action := &game.Action{Type: game.ActionType(m[3])}
+
+	hasUser := m[2] != ""
+	if hasUser {
+		userID, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("ユーザーIDが不正です: %s", m[2])
+		}
+		action.UserID = userID
+	}
+
+	cards, err := p.parseCards(m[4])
+	if err != nil {
+		return err
+	}
+	for _, c := range cards {
+		action.CardIDs = append(action.CardIDs, c.ID)
+	}
+
+	switch action.Type {
+	case game.ActionPlay, game.ActionResolveEffect, game.ActionExchange:
+		if !hasUser || len(cards) == 0 {
+			return fmt.Errorf("%s にはユーザーIDとカードが必要です", action.Type)
+		}
+	case game.ActionPass, game.ActionLeave:
+		if !hasUser || len(cards) > 0 {
+			return fmt.Errorf("%s にはユーザーIDだけを書いてください", action.Type)
+		}
+	case game.ActionAutoExchange:
+		if hasUser || len(cards) > 0 {
+			return fmt.Errorf("%s には何も付けないでください", action.Type)
+		}
+	default:
+		return fmt.Errorf("不明な行動です: %s", action.Type)
+	}
+
+	p.rec.Record.Actions = append(actions, action)
+	return nil
+}
+
+// finish は全体の整合性を確認する
+func (p *parser) finish() error {
+	if err := p.checkTags(); err != nil {
+		return err
+	}
+
+	r := p.rec.Record
+	if len(r.Seats) < 2 {
+		return fmt.Errorf("席は2つ以上必要です")
+	}
+	if len(p.dealt) != len(p.deck) {
+		return fmt.Errorf("配られたカードが %d 枚足りません", len(p.deck)-len(p.dealt))
+	}
+
+	// 乱数の種があれば、種から同じ手札が配られることを確認する
+	if r.Seed != "" {
+		if err := game.VerifyDeal(r); err != nil {
+			return err
+		}
+	}
+
+	// 全ての行動を再生して確認する
+	if _, err := game.Replay(r, len(r.Actions)); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package notation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/model"
+)
+
+// playOut は出せる最初の手を出し、それ以外は代わりの行動で最後まで進める
+func playOut(t *testing.T, g *game.Game) {
+	t.Helper()
+	for step := 0; !g.IsFinished; step++ {
+		if step > 1000 {
+			t.Fatal("ゲームが終わらない")
+		}
+		uid := g.WaitingUserIDs()[0]
+		if moves := g.LegalMoves(uid); len(moves) > 0 {
+			if err := g.Play(uid, moves[0]); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if !g.ForceAction(uid) {
+			t.Fatalf("ユーザー %d が行動できない（%s）", uid, g.Phase())
+		}
+	}
+}
+
+func finishedIDs(players []*game.Player) []int64 {
+	ids := make([]int64, len(players))
+	for i, p := range players {
+		ids[i] = p.UserID
+	}
+	return ids
+}
+
+func newRecord(t *testing.T, rules game.RuleSet, seed byte, exchange bool) (*model.GameRecord, *game.Game) {
+	t.Helper()
+	g := game.NewGameWithSeed([]int64{1, 2, -1, -2}, rules, game.Seed{seed})
+	if exchange {
+		// 前回の順位でカード交換をしてからもう1ゲーム
+		playOut(t, g)
+		g.ResetWithSeed(rules, game.Seed{seed, 1})
+	}
+	playOut(t, g)
+
+	return &model.GameRecord{
+		RoomID:     3,
+		Record:     g.Record,
+		FinishedAt: time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+	}, g
+}
+
+func TestRoundTrip(t *testing.T) {
+	full := game.RuleSet{
+		EightCut: true, ElevenBack: true, SpadeThree: true, Revolution: true, MiyakoOchi: true,
+		Shibari: true, SevenPass: true, TenDiscard: true, NineReverse: true, FiveSkip: true,
+		ForbiddenFinish: true, JokerCount: 2, TurnTimeLimit: 30,
+	}
+	tests := []struct {
+		name     string
+		rules    game.RuleSet
+		exchange bool
+	}{
+		{"標準ルール", game.DefaultRuleSet(), false},
+		{"全ルール", full, false},
+		{"ジョーカーなし", game.RuleSet{}, false},
+		{"カード交換あり", game.DefaultRuleSet(), true},
+		{"全ルールでカード交換あり", full, true},
+	}
+
+	for _, tt := range tests {
+		for seed := byte(0); seed < 5; seed++ {
+			t.Run(fmt.Sprintf("%s/%d", tt.name, seed), func(t *testing.T) {
+				rec, g := newRecord(t, tt.rules, seed, tt.exchange)
+
+				data, err := Encode(rec)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := Decode(data)
+				if err != nil {
+					t.Fatalf("%v\n%s", err, data)
+				}
+
+				if got.RoomID != rec.RoomID || !got.FinishedAt.Equal(rec.FinishedAt) {
+					t.Errorf("タグが一致しない: %d %v", got.RoomID, got.FinishedAt)
+				}
+				if !reflect.DeepEqual(got.Record, rec.Record) {
+					t.Errorf("記録が一致しない\n%s", data)
+				}
+
+				// 読み込んだ記録を再生すると同じ順位になる
+				replayed, err := game.Replay(got.Record, len(got.Record.Actions))
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := finishedIDs(g.FinishedPlayers)
+				if result := finishedIDs(replayed.FinishedPlayers); !reflect.DeepEqual(result, want) {
+					t.Errorf("順位 = %v, want %v", result, want)
+				}
+
+				// もう一度書き出しても同じ棋譜になる
+				again, err := Encode(got)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(again) != string(data) {
+					t.Errorf("書き出し直した棋譜が変わった\n%s\n---\n%s", data, again)
+				}
+			})
+		}
+	}
+}
+
+func TestCardToken(t *testing.T) {
+	for jokers := 0; jokers <= 2; jokers++ {
+		deck := game.NewDeck(jokers)
+		seen := make(map[string]bool)
+		for i, c := range deck {
+			token := cardToken(c)
+			if seen[token] {
+				t.Errorf("表記 %s が重複している", token)
+			}
+			seen[token] = true
+
+			if c.Suit == game.SuitJoker {
+				// ジョーカーはデッキの最後に並び、IDから番号を振る
+				if want := fmt.Sprintf("Joker%d", i-51); token != want {
+					t.Errorf("ID %d のジョーカーの表記 = %s, want %s", c.ID, token, want)
+				}
+				if c.ID <= 52 {
+					t.Errorf("ジョーカーのIDが52以下: %d", c.ID)
+				}
+			}
+			if got := cardByID(jokers, c.ID); got == nil || cardToken(got) != token {
+				t.Errorf("ID %d から同じカードを引けない", c.ID)
+			}
+		}
+		if len(seen) != 52+jokers {
+			t.Errorf("ジョーカー %d 枚のデッキの表記が %d 種類", jokers, len(seen))
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	rec, _ := newRecord(t, game.DefaultRuleSet(), 0, false)
+	data, err := Encode(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := string(data)
+	lines := strings.Split(valid, "\n")
+
+	// 行の番号を探す
+	lineWith := func(prefix string) int {
+		for i, l := range lines {
+			if strings.HasPrefix(l, prefix) {
+				return i
+			}
+		}
+		t.Fatalf("%s で始まる行がない", prefix)
+		return -1
+	}
+	replaceLine := func(i int, s string) string {
+		out := append([]string{}, lines...)
+		out[i] = s
+		return strings.Join(out, "\n")
+	}
+	insertLine := func(i int, s string) string {
+		out := append([]string{}, lines[:i]...)
+		out = append(out, s)
+		out = append(out, lines[i:]...)
+		return strings.Join(out, "\n")
+	}
+
+	seat1 := lineWith("seat 1 ")
+	seat2 := lineWith("seat 2 ")
+	step2 := lineWith("2. ")
+	firstCard := strings.Fields(strings.SplitN(lines[seat1], ":", 2)[1])[0]
+	secondSeatCards := strings.Fields(strings.SplitN(lines[seat2], ":", 2)[1])
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"コメントと空行は読み飛ばす", "# comment\n\n" + valid, ""},
+		{"不明なタグ", insertLine(0, `[Foo "x"]`), "不明なタグです"},
+		{"タグの書式", insertLine(0, `[Foo x]`), "タグの書式が不正です"},
+		{"形式のバージョン", replaceLine(lineWith("[Format"), `[Format "daifugo/2"]`), "対応していない形式です"},
+		{"タグの重複", insertLine(0, `[RoomID "4"]`), "タグ RoomID が重複しています"},
+		{"必須のタグがない", replaceLine(lineWith("[Rules"), ""), "タグ Rules がありません"},
+		{"不明なルール", replaceLine(lineWith("[Rules"), `[Rules "eight_cut unknown"]`), "不明なルールです"},
+		{"ルールの重複", replaceLine(lineWith("[Rules"), `[Rules "eight_cut eight_cut"]`), "ルール eight_cut が重複しています"},
+		{"席の後のタグ", insertLine(seat2, `[Seed "00"]`), "タグは席より前に書いてください"},
+		{"席の重複", insertLine(seat2, lines[seat1]), "ユーザー 1 の席が重複しています"},
+		{"ユーザーID 0 の席", replaceLine(seat1, strings.Replace(lines[seat1], "seat 1 ", "seat 0 ", 1)), "ユーザーIDが不正です"},
+		{"カードの重複", replaceLine(seat2, "seat 2 rank 0: "+strings.Join(append([]string{firstCard}, secondSeatCards[1:]...), " ")), "が重複しています"},
+		{"不明なカード", replaceLine(seat1, lines[seat1]+" ♠14"), "不明なカードです"},
+		{"ジョーカーの枚数を超える番号", replaceLine(seat1, lines[seat1]+" Joker3"), "不明なカードです: Joker3"},
+		{"配られたカードが足りない", replaceLine(seat2, "seat 2 rank 0: "+strings.Join(secondSeatCards[1:], " ")), "枚足りません"},
+		{"手数の飛び", replaceLine(step2, strings.Replace(lines[step2], "2.", "3.", 1)), "2手目が来るべき位置です"},
+		{"行動の後の席", insertLine(step2, "seat 9 rank 0:"), "席は行動より前に書いてください"},
+		{"不明な行動", insertLine(step2, "2. 1 jump"), "不明な行動です"},
+		{"パスにカード", insertLine(step2, "2. 1 pass "+firstCard), "pass にはユーザーIDだけを書いてください"},
+		{"カードのない出し札", insertLine(step2, "2. 1 play"), "play にはユーザーIDとカードが必要です"},
+		{"自動交換にユーザー", insertLine(step2, "2. 1 auto_exchange"), "auto_exchange には何も付けないでください"},
+		{"手番でない人の行動", replaceLine(step2, "2. -2 pass"), "2手目を再現できません"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode([]byte(tt.data))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("読み込めない: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("エラーにならない（want %q）", tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("エラー = %q, want %q を含む", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package presence
+
+import (
+	"sync"
+	"time"
+)
+
+// status はユーザーごとの接続状況
+type status struct {
+	conns  int
+	online bool
+	// 最後に接続していた時刻（接続中なら最後に接続した時刻）
+	lastSeen time.Time
+	// 猶予期間後にオフラインにするタイマー
+	timer *time.Timer
+	// 古いタイマーを無視するための番号
+	gen int
+}
+
+// Tracker は SSE や WebSocket の接続数からユーザーのオンライン状態を管理する
+// 接続が切れてもすぐにはオフラインにせず、猶予期間内の再接続ならオンラインのまま扱う
+type Tracker struct {
+	mu       sync.Mutex
+	grace    time.Duration
+	users    map[int64]*status
+	onChange func(userID int64, online bool)
+}
+
+// NewTracker は Tracker を作成する
+func NewTracker(grace time.Duration) *Tracker {
+	return &Tracker{
+		grace: grace,
+		users: make(map[int64]*status),
+	}
+}
+
+// OnChange はオンライン状態が変わったときに呼ぶ関数を設定する
+func (t *Tracker) OnChange(f func(userID int64, online bool)) {
+	t.mu.Lock()
+	t.onChange = f
+	t.mu.Unlock()
+}
+
+// Connect はユーザーの接続を1つ数える
+func (t *Tracker) Connect(userID int64) {
+	t.mu.Lock()
+	st, ok := t.users[userID]
+	if !ok {
+		st = &status{}
+		t.users[userID] = st
+	}
+	st.conns++
+	st.lastSeen = time.Now()
+	if st.timer != nil {
+		st.timer.Stop()
+		st.timer = nil
+	}
+	changed := !st.online
+	st.online = true
+	onChange := t.onChange
+	t.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange(userID, true)
+	}
+}
+
+// Disconnect はユーザーの接続を1つ減らし、なくなったら猶予期間後にオフラインにする
+func (t *Tracker) Disconnect(userID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.users[userID]
+	if !ok || st.conns == 0 {
+		return
+	}
+	st.conns--
+	st.lastSeen = time.Now()
+	if st.conns > 0 {
+		return
+	}
+
+	st.gen++
+	gen := st.gen
+	st.timer = time.AfterFunc(t.grace, func() {
+		t.expire(userID, st, gen)
+	})
+}
+
+func (t *Tracker) expire(userID int64, st *status, gen int) {
+	t.mu.Lock()
+	// 猶予期間中に再接続していた
+	if st.gen != gen || st.conns > 0 || !st.online {
+		t.mu.Unlock()
+		return
+	}
+	st.online = false
+	st.timer = nil
+	onChange := t.onChange
+	t.mu.Unlock()
+
+	if onChange != nil {
+		onChange(userID, false)
+	}
+}
+
+// Status はオンラインかと最後に接続していた時刻を返す（一度も接続していなければゼロ値）
+func (t *Tracker) Status(userID int64) (bool, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.users[userID]
+	if !ok {
+		return false, time.Time{}
+	}
+	return st.online, st.lastSeen
+}
+
+// OfflineSince はオフラインになったユーザーの、最後に接続していた時刻を返す
+// 接続中・猶予期間中のユーザーと、一度も接続していないユーザーは false を返す
+func (t *Tracker) OfflineSince(userID int64) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.users[userID]
+	if !ok || st.online {
+		return time.Time{}, false
+	}
+	return st.lastSeen, true
+}
+
+// OfflineBefore は before より前からオフラインのユーザーのIDを返す
+func (t *Tracker) OfflineBefore(before time.Time) []int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var ids []int64
+	for uid, st := range t.users {
+		if !st.online && st.lastSeen.Before(before) {
+			ids = append(ids, uid)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ne241099/daifugo-server/internal/auth"
+	"github.com/ne241099/daifugo-server/internal/sse"
+	"github.com/ne241099/daifugo-server/usecase/room"
+)
+
+// newRoomAuthorizer は部屋のメンバーか観戦者だけに部屋のイベントの購読を許可する
+func newRoomAuthorizer(uc room.GetRoomUseCase) sse.RoomAuthorizer {
+	return func(ctx context.Context, userID, roomID int64) error {
+		r, err := uc.Execute(ctx, roomID)
+		if err != nil {
+			return err
+		}
+
+		if !r.IsMember(userID) && !r.IsSpectator(userID) {
+			return errors.New("部屋に参加していません")
+		}
+		return nil
+	}
+}
+
+// newIssueTicketHandler は /events に接続するための使い捨てチケットを発行するハンドラを返す
+func newIssueTicketHandler(tickets *sse.TicketStore) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, err := auth.GetUserID(c.Request().Context())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+		}
+
+		ticket, expiresAt, err := tickets.Issue(userID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, map[string]any{
+			"ticket":    ticket,
+			"expiresAt": expiresAt,
+		})
+	}
+}
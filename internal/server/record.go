@@ -0,0 +1,39 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ne241099/daifugo-server/internal/notation"
+	"github.com/ne241099/daifugo-server/repository"
+	"github.com/ne241099/daifugo-server/usecase/game"
+)
+
+// newDownloadRecordHandler は終了したゲームの棋譜をテキストで返すハンドラを返す
+func newDownloadRecordHandler(uc game.GetGameRecordUseCase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid record id")
+		}
+
+		record, err := uc.Execute(c.Request().Context(), id)
+		if err != nil {
+			if errors.Is(err, repository.ErrEntityNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, "record not found")
+			}
+			return err
+		}
+
+		text, err := notation.Encode(record)
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="daifugo-%d.txt"`, record.ID))
+		return c.Blob(http.StatusOK, "text/plain; charset=utf-8", text)
+	}
+}
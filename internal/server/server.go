@@ -1,15 +1,25 @@
 package server
 
 import (
+	"time"
+
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/ne241099/daifugo-server/graph"
 	internalMiddleware "github.com/ne241099/daifugo-server/internal/middleware"
 	"github.com/ne241099/daifugo-server/internal/sse"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
+// フロントエンドとの通信用に許可するオリジン
+var allowedOrigins = []string{"http://localhost:5173"}
+
 // New は設定済みの Echo サーバーインスタンスを返す
 // 必要な依存関係（ResolverやHub）は引数として受け取る
 func New(resolver *graph.Resolver, hub *sse.Hub, authMiddleware *internalMiddleware.AuthMiddleware) *echo.Echo {
@@ -19,19 +29,37 @@ func New(resolver *graph.Resolver, hub *sse.Hub, authMiddleware *internalMiddlew
 	e.Use(middleware.RequestLogger())
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		// フロントエンドとの通信用
-		AllowOrigins: []string{"http://localhost:5173"},
+		AllowOrigins: allowedOrigins,
 		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
 	}))
 	// 認証ミドルウェアの適用
 	e.Use(echo.WrapMiddleware(authMiddleware.Authenticate))
 
 	// GraphQL サーバーの設定
-	gqlServer := handler.NewDefaultServer(
+	// 購読は WebSocket（graphql-ws / graphql-transport-ws）で受け付ける
+	gqlServer := handler.New(
 		graph.NewExecutableSchema(
 			graph.Config{Resolvers: resolver},
 		),
 	)
+	gqlServer.AddTransport(transport.Websocket{
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: checkOrigin,
+		},
+		InitFunc:              newWebsocketInit(authMiddleware),
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	gqlServer.AddTransport(transport.Options{})
+	gqlServer.AddTransport(transport.GET{})
+	gqlServer.AddTransport(transport.POST{})
+	gqlServer.AddTransport(transport.MultipartForm{})
+
+	gqlServer.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	gqlServer.Use(extension.Introspection{})
+	gqlServer.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New[string](100),
+	})
 
 	// ルーティングの定義
 
@@ -52,7 +80,13 @@ func New(resolver *graph.Resolver, hub *sse.Hub, authMiddleware *internalMiddlew
 	})
 
 	// SSE エンドポイント
-	e.GET("/events", sse.NewHandler(hub))
+	// ブラウザの EventSource 向けに、先にチケットを発行してから接続する
+	tickets := sse.NewTicketStore()
+	e.POST("/events/ticket", newIssueTicketHandler(tickets))
+	e.GET("/events", sse.NewHandler(hub, tickets, newRoomAuthorizer(resolver.GetRoomUseCase)))
+
+	// 棋譜のダウンロード
+	e.GET("/records/:id", newDownloadRecordHandler(resolver.GetGameRecordUseCase))
 
 	return e
 }
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/ne241099/daifugo-server/internal/auth"
+	internalMiddleware "github.com/ne241099/daifugo-server/internal/middleware"
+)
+
+// newWebsocketInit は connection_init のペイロードの Authorization で接続を認証する
+// ブラウザの WebSocket はヘッダーを付けられないため、トークンはペイロードで受け取る
+// 接続時のリクエストで認証済みならペイロードは省略できる
+func newWebsocketInit(authMiddleware *internalMiddleware.AuthMiddleware) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		token := strings.TrimPrefix(payload.Authorization(), "Bearer ")
+		if token == "" {
+			if _, err := auth.GetUserID(ctx); err != nil {
+				return nil, nil, errors.New("unauthorized")
+			}
+			return ctx, nil, nil
+		}
+
+		uid, err := authMiddleware.VerifyToken(ctx, token)
+		if err != nil {
+			return nil, nil, errors.New("unauthorized")
+		}
+		return auth.WithUserID(ctx, uid), nil, nil
+	}
+}
+
+// checkOrigin は CORS と同じオリジンからの WebSocket 接続だけを許可する
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || slices.Contains(allowedOrigins, origin)
+}
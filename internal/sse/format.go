@@ -14,9 +14,11 @@ func writeSSE(w io.Writer, ev Event) error {
 			return err
 		}
 	}
-	// id
-	if _, err := fmt.Fprintf(w, "id: %d\n", ev.ID); err != nil {
-		return err
+	// id（0 は履歴に残らないイベントなので送らず、クライアントの Last-Event-ID を保つ）
+	if ev.ID != 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", ev.ID); err != nil {
+			return err
+		}
 	}
 	// event（任意）
 	if ev.Type != "" {
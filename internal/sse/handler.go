@@ -2,19 +2,58 @@ package sse
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/ne241099/daifugo-server/internal/auth"
 )
 
+// RoomAuthorizer はユーザーが部屋のイベントを購読できるか（メンバーか観戦者か）確認する
+type RoomAuthorizer func(ctx context.Context, userID, roomID int64) error
+
 // NewHandler は /events 用の Echo ハンドラを返す
-func NewHandler(hub *Hub) echo.HandlerFunc {
+// 接続には ?ticket=<チケット> か Authorization ヘッダーでの認証が必要
+// ロビーと本人宛てのイベントが届き、?room=<id> を指定すると参加・観戦中の部屋のイベントも届く
+// Last-Event-ID を付けて再接続すると、切断中のイベントを再送する
+// 再送できない場合や取りこぼしがあった場合は resync_required を送るので、クライアントは状態を取得し直す
+func NewHandler(hub *Hub, tickets *TicketStore, authorize RoomAuthorizer) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		res := c.Response()
 		req := c.Request()
 
+		userID, err := authenticate(c, tickets)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+		}
+		// 切断後もしばらくは同じチケットで再接続できるようにする
+		if v := c.QueryParam("ticket"); v != "" {
+			defer tickets.Release(v)
+		}
+
+		var lastEventID int64
+		if v := req.Header.Get("Last-Event-ID"); v != "" {
+			lastEventID, err = strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid Last-Event-ID")
+			}
+		}
+
+		topics := []string{LobbyTopic}
+		for _, v := range c.QueryParams()["room"] {
+			roomID, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid room id")
+			}
+			if err := authorize(req.Context(), userID, roomID); err != nil {
+				return echo.NewHTTPError(http.StatusForbidden, err.Error())
+			}
+			topics = append(topics, RoomTopic(roomID))
+		}
+
 		// SSE 必須ヘッダ
 		res.Header().Set(echo.HeaderContentType, "text/event-stream")
 		res.Header().Set(echo.HeaderCacheControl, "no-cache")
@@ -27,8 +66,8 @@ func NewHandler(hub *Hub) echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusInternalServerError, "streaming unsupported")
 		}
 
-		// クライアント登録
-		cl := hub.Subscribe()
+		// クライアント登録と、切断中のイベントの取得
+		cl, missed, complete := hub.Resume(userID, lastEventID, topics...)
 		defer hub.Unsubscribe(cl)
 
 		// 接続直後に1回送る（任意）
@@ -38,6 +77,15 @@ func NewHandler(hub *Hub) echo.HandlerFunc {
 			Data: map[string]any{"ok": true, "time": time.Now().Format(time.RFC3339Nano)},
 			Time: time.Now().Format(time.RFC3339Nano),
 		})
+		if complete {
+			for _, ev := range missed {
+				if err := writeSSE(res.Writer, ev); err != nil {
+					return nil
+				}
+			}
+		} else {
+			_ = writeSSE(res.Writer, resyncEvent("history_unavailable"))
+		}
 		flusher.Flush()
 
 		ctx := req.Context()
@@ -52,14 +100,15 @@ func NewHandler(hub *Hub) echo.HandlerFunc {
 			case <-keepAlive.C:
 				_, _ = fmt.Fprint(res.Writer, ": ping\n\n")
 				flusher.Flush()
+			case <-cl.Dropped():
+				if err := writeSSE(res.Writer, resyncEvent("events_dropped")); err != nil {
+					return nil
+				}
+				flusher.Flush()
 			case ev, ok := <-cl.ch:
 				if !ok {
 					return nil
 				}
-				// Publish側で Time を入れていないので、ここで入れる
-				if ev.Time == "" {
-					ev.Time = time.Now().Format(time.RFC3339Nano)
-				}
 				if err := writeSSE(res.Writer, ev); err != nil {
 					return nil
 				}
@@ -69,6 +118,24 @@ func NewHandler(hub *Hub) echo.HandlerFunc {
 	}
 }
 
-// context を使った認証注入などをしたい場合は、main 側 middleware で
-// c.SetRequest(c.Request().WithContext(ctx)) の形で入れてください。
-var _ context.Context
+// authenticate はチケット、なければ認証ミドルウェアが設定したユーザーIDで接続者を特定する
+func authenticate(c echo.Context, tickets *TicketStore) (int64, error) {
+	if v := c.QueryParam("ticket"); v != "" {
+		userID, ok := tickets.Redeem(v)
+		if !ok {
+			return 0, errors.New("invalid ticket")
+		}
+		return userID, nil
+	}
+	return auth.GetUserID(c.Request().Context())
+}
+
+// resyncEvent はクライアントに状態の取得し直しを求めるイベントを作る
+// ID を持たないので、クライアントの Last-Event-ID は変わらない
+func resyncEvent(reason string) Event {
+	return Event{
+		Type: "resync_required",
+		Data: map[string]any{"reason": reason},
+		Time: time.Now().Format(time.RFC3339Nano),
+	}
+}
@@ -1,82 +1,294 @@
 package sse
 
-import "sync"
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LobbyTopic はロビー（部屋一覧）のイベントのトピック
+const LobbyTopic = "lobby"
+
+// RoomTopic は部屋ごとのイベントのトピックを返す
+func RoomTopic(roomID int64) string {
+	return "room:" + strconv.FormatInt(roomID, 10)
+}
+
+// UserTopic はユーザー本人だけに届けるイベントのトピックを返す（手札など）
+func UserTopic(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
 
 // Event は SSE で配信するイベント
 type Event struct {
 	ID    int64  `json:"id"`
+	Topic string `json:"topic"`
 	Type  string `json:"type"`
 	Data  any    `json:"data"`
 	Time  string `json:"time"`
 	Retry *int   `json:"retry_ms,omitempty"`
+
+	// 届ける相手を絞る場合の判定（nil ならトピックの購読者全員）
+	to func(userID int64) bool
+}
+
+// deliverable はイベントをユーザーへ届けてよいか判定する
+func (ev *Event) deliverable(userID int64) bool {
+	return ev.to == nil || ev.to(userID)
 }
 
-// Client は各接続の送信キュー
+const (
+	// 再接続時に再送できるよう、トピックごとに保持するイベントの数
+	historySize = 256
+	// 配信が途絶えたトピックの履歴を破棄するまでの時間
+	historyTTL = 10 * time.Minute
+)
+
+// Client は各接続の送信キューと購読中のトピック
 type Client struct {
-	ch chan Event
+	ch     chan Event
+	userID int64
+	topics map[string]struct{}
+	// 送信キューがあふれてイベントを取りこぼしたことを知らせる
+	dropped chan struct{}
+}
+
+// Events は配信されたイベントを受け取るチャネルを返す（Unsubscribe すると閉じる）
+// 購読中の全トピックのイベントが届くので、必要なら Event.Topic で絞り込む
+func (c *Client) Events() <-chan Event {
+	return c.ch
+}
+
+// Dropped はイベントを取りこぼした場合に通知されるチャネルを返す
+func (c *Client) Dropped() <-chan struct{} {
+	return c.dropped
+}
+
+// history はトピックごとの直近のイベントを保持するリングバッファ
+type history struct {
+	events []Event
+	next   int
+	// 古いものから捨てた中で最も新しいイベントの ID
+	evictedID int64
+	updatedAt time.Time
+}
+
+func (r *history) add(ev Event, now time.Time) {
+	r.updatedAt = now
+	if len(r.events) < historySize {
+		r.events = append(r.events, ev)
+		return
+	}
+	r.evictedID = r.events[r.next].ID
+	r.events[r.next] = ev
+	r.next = (r.next + 1) % historySize
 }
 
-// Hub はクライアント管理とブロードキャストを担当
+// since は lastID より後のイベントを古い順に返す
+// 必要なイベントがすでに捨てられている場合は false を返す
+func (r *history) since(lastID int64) ([]Event, bool) {
+	if r.evictedID > lastID {
+		return nil, false
+	}
+
+	var out []Event
+	for i := range r.events {
+		ev := r.events[(r.next+i)%len(r.events)]
+		if ev.ID > lastID {
+			out = append(out, ev)
+		}
+	}
+	return out, true
+}
+
+// Presence は接続の増減を受け取ってオンライン状態を管理する
+type Presence interface {
+	Connect(userID int64)
+	Disconnect(userID int64)
+}
+
+// Hub はクライアント管理とトピックごとの配信を担当
 type Hub struct {
-	mu      sync.Mutex
-	clients map[*Client]struct{}
-	nextID  int64
+	mu       sync.Mutex
+	clients  map[*Client]struct{}
+	presence Presence
+	history  map[string]*history
+	// 破棄した履歴に含まれていた最も新しいイベントの ID
+	prunedID int64
+	nextID   int64
 }
 
 // NewHub は Hub を作成する
 func NewHub() *Hub {
 	return &Hub{
 		clients: make(map[*Client]struct{}),
+		history: make(map[string]*history),
 		nextID:  1,
 	}
 }
 
+// SetPresence は接続の増減を通知する先を設定する
+func (h *Hub) SetPresence(p Presence) {
+	h.mu.Lock()
+	h.presence = p
+	h.mu.Unlock()
+}
+
 // Subscribe は新しいクライアントを登録して返す
-func (h *Hub) Subscribe() *Client {
-	c := &Client{ch: make(chan Event, 32)}
+// 本人宛てのトピックは自動で購読する
+func (h *Hub) Subscribe(userID int64, topics ...string) *Client {
+	c, _, _ := h.Resume(userID, 0, topics...)
+	return c
+}
+
+// Resume はクライアントを登録し、lastEventID より後に配信されたイベントを返す
+// 登録と取得を同時に行うので、再送と新しいイベントの間で取りこぼしや重複は起きない
+// 履歴が足りず再送できない場合は false を返すので、クライアントに再取得させる
+func (h *Hub) Resume(userID, lastEventID int64, topics ...string) (*Client, []Event, bool) {
+	c := &Client{
+		ch:      make(chan Event, 32),
+		userID:  userID,
+		topics:  make(map[string]struct{}, len(topics)+1),
+		dropped: make(chan struct{}, 1),
+	}
+	c.topics[UserTopic(userID)] = struct{}{}
+	for _, t := range topics {
+		c.topics[t] = struct{}{}
+	}
+
 	h.mu.Lock()
 	h.clients[c] = struct{}{}
+	missed, complete := h.missedSince(c, lastEventID)
+	presence := h.presence
 	h.mu.Unlock()
-	return c
+
+	// 通知先が Publish することもあるので、ロックを外してから通知する
+	if presence != nil {
+		presence.Connect(userID)
+	}
+
+	return c, missed, complete
+}
+
+// missedSince はクライアントが購読するトピックの、lastEventID より後のイベントを返す（h.mu を取得した状態で呼ぶ）
+func (h *Hub) missedSince(c *Client, lastEventID int64) ([]Event, bool) {
+	if lastEventID <= 0 {
+		return nil, true
+	}
+	// サーバーの再起動などで ID が振り直されているか、必要な履歴が破棄されている
+	if lastEventID >= h.nextID || lastEventID < h.prunedID {
+		return nil, false
+	}
+
+	var missed []Event
+	for t := range c.topics {
+		r, ok := h.history[t]
+		if !ok {
+			continue
+		}
+		events, ok := r.since(lastEventID)
+		if !ok {
+			return nil, false
+		}
+		for _, ev := range events {
+			if ev.deliverable(c.userID) {
+				missed = append(missed, ev)
+			}
+		}
+	}
+	sort.Slice(missed, func(i, j int) bool { return missed[i].ID < missed[j].ID })
+
+	return missed, true
 }
 
 // Unsubscribe はクライアントを削除する
 func (h *Hub) Unsubscribe(c *Client) {
 	h.mu.Lock()
-	if _, ok := h.clients[c]; ok {
+	_, ok := h.clients[c]
+	if ok {
 		delete(h.clients, c)
 		close(c.ch)
 	}
+	presence := h.presence
 	h.mu.Unlock()
+
+	if ok && presence != nil {
+		presence.Disconnect(c.userID)
+	}
 }
 
-// Publish は全クライアントへイベントを配信する（遅いクライアントは drop）
-func (h *Hub) Publish(eventType string, data any, retry *int) Event {
+// Leave はユーザーの接続からトピックの購読を外す（部屋を退出した場合など）
+func (h *Hub) Leave(topic string, userID int64) {
 	h.mu.Lock()
-	id := h.nextID
-	h.nextID++
+	defer h.mu.Unlock()
 
-	clients := make([]*Client, 0, len(h.clients))
 	for c := range h.clients {
-		clients = append(clients, c)
+		if c.userID == userID {
+			delete(c.topics, topic)
+		}
 	}
-	h.mu.Unlock()
+}
+
+// Publish はトピックを購読しているクライアントへイベントを配信する
+// 送信キューがあふれたクライアントには取りこぼしを通知する
+func (h *Hub) Publish(topic, eventType string, data any, retry *int) Event {
+	return h.PublishTo(topic, eventType, data, retry, nil)
+}
 
+// PublishTo はトピックを購読しているクライアントのうち、to が true を返すユーザーだけへイベントを配信する
+// 再接続時の再送にも同じ判定を使う（to が nil なら Publish と同じ）
+func (h *Hub) PublishTo(topic, eventType string, data any, retry *int, to func(userID int64) bool) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
 	ev := Event{
-		ID:    id,
+		ID:    h.nextID,
+		Topic: topic,
 		Type:  eventType,
 		Data:  data,
 		Retry: retry,
-		// Time は handler 側で設定してもよいが、ここで統一してもOK
+		Time:  now.Format(time.RFC3339Nano),
+		to:    to,
 	}
+	h.nextID++
 
-	for _, c := range clients {
+	r, ok := h.history[topic]
+	if !ok {
+		h.pruneHistory(now)
+		r = &history{}
+		h.history[topic] = r
+	}
+	r.add(ev, now)
+
+	// ロックしたまま送ることで、各クライアントに ID 順で届くようにする
+	for c := range h.clients {
+		if _, ok := c.topics[topic]; !ok || !ev.deliverable(c.userID) {
+			continue
+		}
 		select {
 		case c.ch <- ev:
 		default:
-			// drop（要件次第で Unsubscribe しても良い）
+			select {
+			case c.dropped <- struct{}{}:
+			default:
+			}
 		}
 	}
 
 	return ev
 }
+
+// pruneHistory は配信が途絶えたトピック（削除された部屋など）の履歴を破棄する（h.mu を取得した状態で呼ぶ）
+func (h *Hub) pruneHistory(now time.Time) {
+	for t, r := range h.history {
+		if now.Sub(r.updatedAt) < historyTTL {
+			continue
+		}
+		for _, ev := range r.events {
+			h.prunedID = max(h.prunedID, ev.ID)
+		}
+		delete(h.history, t)
+	}
+}
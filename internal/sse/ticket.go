@@ -0,0 +1,93 @@
+package sse
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TicketTTL はチケットの有効期間
+const TicketTTL = 30 * time.Second
+
+type ticket struct {
+	userID    int64
+	expiresAt time.Time
+	// 接続中のチケットは期限切れにならず、他の接続では使えない
+	inUse bool
+}
+
+// TicketStore は SSE 接続用の短期間のチケットを管理する
+// EventSource は Authorization ヘッダーを付けられないため、
+// 認証済みの API で発行したチケットをクエリで渡して接続する
+// EventSource は切断すると同じ URL で再接続するので、切断後も TicketTTL の間は同じチケットを使える
+type TicketStore struct {
+	mu      sync.Mutex
+	tickets map[string]ticket
+}
+
+// NewTicketStore は TicketStore を作成する
+func NewTicketStore() *TicketStore {
+	return &TicketStore{
+		tickets: make(map[string]ticket),
+	}
+}
+
+// Issue はユーザーのチケットを発行し、チケットと有効期限を返す
+func (s *TicketStore) Issue(userID int64) (string, time.Time, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	value := hex.EncodeToString(b)
+
+	now := time.Now()
+	expiresAt := now.Add(TicketTTL)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 期限切れのチケットを掃除する
+	for k, t := range s.tickets {
+		if !t.inUse && now.After(t.expiresAt) {
+			delete(s.tickets, k)
+		}
+	}
+	s.tickets[value] = ticket{userID: userID, expiresAt: expiresAt}
+
+	return value, expiresAt, nil
+}
+
+// Redeem はチケットを接続中にしてユーザーIDを返す
+// 存在しない・期限切れ・他の接続で使用中のチケットは false を返す
+func (s *TicketStore) Redeem(value string) (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tickets[value]
+	if !ok || t.inUse {
+		return 0, false
+	}
+	if time.Now().After(t.expiresAt) {
+		delete(s.tickets, value)
+		return 0, false
+	}
+
+	t.inUse = true
+	s.tickets[value] = t
+	return t.userID, true
+}
+
+// Release は接続が切れたチケットを、再接続のために TicketTTL の間だけ有効にしておく
+func (s *TicketStore) Release(value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tickets[value]
+	if !ok {
+		return
+	}
+	t.inUse = false
+	t.expiresAt = time.Now().Add(TicketTTL)
+	s.tickets[value] = t
+}
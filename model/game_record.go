@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ne241099/daifugo-server/internal/game"
+)
+
+// GameRecord は終了したゲームの記録
+type GameRecord struct {
+	ID         int64        `json:"id"`
+	RoomID     int64        `json:"room_id"`
+	Record     *game.Record `json:"record"`
+	FinishedAt time.Time    `json:"finished_at"`
+}
@@ -1,43 +1,192 @@
 package model
 
 import (
-	"sync"
+	"errors"
 	"time"
 
 	"github.com/ne241099/daifugo-server/internal/game"
 )
 
+// 1部屋あたりの観戦者の上限
+const MaxSpectators = 10
+
+// 観戦者に全員の手札を見せる場合に遅らせるデフォルトの手数
+const DefaultSpectatorDelay = 8
+
 type Room struct {
-	ID        int64         `json:"id"`
-	Name      string        `json:"name"`
-	OwnerID   int64         `json:"owner_id"`
-	MemberIDs []int64       `json:"member_ids"`
-	Game      *game.Game    `json:"game"`
-	PrevRanks map[int64]int `json:"prev_ranks"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
-	Mu        sync.Mutex    `json:"-"`
+	ID           int64         `json:"id"`
+	Name         string        `json:"name"`
+	OwnerID      int64         `json:"owner_id"`
+	MemberIDs    []int64       `json:"member_ids"`
+	BotIDs       []int64       `json:"bot_ids"`
+	SpectatorIDs []int64       `json:"spectator_ids"`
+	Game         *game.Game    `json:"game"`
+	PrevRanks    map[int64]int `json:"prev_ranks"`
+	Rules        game.RuleSet  `json:"rules"`
+	// 観戦者に遅れて全員の手札を見せるか（オーナーが設定する）
+	SpectatorFullView bool `json:"spectator_full_view"`
+	// 全員の手札を見せる場合に遅らせる手数
+	SpectatorDelay int `json:"spectator_delay"`
+	// 保存するたびに1つ増える版数（同時に更新された場合の競合の検出に使う）
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 func (r *Room) IsFull() bool {
-	return len(r.MemberIDs) >= 4
+	return len(r.SeatIDs()) >= 4
+}
+
+// SeatIDs はゲームの席順（メンバー、ボットの順）を返す
+func (r *Room) SeatIDs() []int64 {
+	ids := make([]int64, 0, len(r.MemberIDs)+len(r.BotIDs))
+	ids = append(ids, r.MemberIDs...)
+	ids = append(ids, r.BotIDs...)
+	return ids
+}
+
+// AddBot はボットの席を追加して、そのIDを返す
+func (r *Room) AddBot() int64 {
+	id := int64(-1)
+	for _, bid := range r.BotIDs {
+		if bid <= id {
+			id = bid - 1
+		}
+	}
+	r.BotIDs = append(r.BotIDs, id)
+	return id
+}
+
+// RemoveBot はボットの席を削除する
+func (r *Room) RemoveBot(botID int64) bool {
+	for i, bid := range r.BotIDs {
+		if bid == botID {
+			r.BotIDs = append(r.BotIDs[:i], r.BotIDs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// IsMember は部屋のメンバーか判定する
+func (r *Room) IsMember(userID int64) bool {
+	for _, mid := range r.MemberIDs {
+		if mid == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSpectator は観戦者か判定する
+func (r *Room) IsSpectator(userID int64) bool {
+	for _, sid := range r.SpectatorIDs {
+		if sid == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// AddSpectator は観戦者を追加する
+func (r *Room) AddSpectator(userID int64) error {
+	if r.IsMember(userID) {
+		return errors.New("members cannot spectate their own room")
+	}
+	if r.IsSpectator(userID) {
+		return nil
+	}
+	if len(r.SpectatorIDs) >= MaxSpectators {
+		return errors.New("too many spectators")
+	}
+	r.SpectatorIDs = append(r.SpectatorIDs, userID)
+	return nil
+}
+
+// RemoveSpectator は観戦者を外す
+func (r *Room) RemoveSpectator(userID int64) bool {
+	for i, sid := range r.SpectatorIDs {
+		if sid == userID {
+			r.SpectatorIDs = append(r.SpectatorIDs[:i], r.SpectatorIDs[i+1:]...)
+			return true
+		}
+	}
+	return false
 }
 
 func (r *Room) StartGame() {
-	r.Game = game.NewGame(r.MemberIDs)
+	r.Game = game.NewGame(r.SeatIDs(), r.Rules)
+	r.Game.SpectatorDelay = r.spectatorDelay()
+}
+
+// RefreshTurnDeadline は次の手番の制限時間を設定する
+func (r *Room) RefreshTurnDeadline() {
+	if r.Game == nil {
+		return
+	}
+	limit := r.Game.Rules.TurnTimeLimit
+	if limit <= 0 || r.Game.IsFinished {
+		r.Game.TurnDeadline = time.Time{}
+		return
+	}
+	r.Game.TurnDeadline = time.Now().Add(time.Duration(limit) * time.Second)
+}
+
+// IsPlaying はゲームが進行中（開始済みで終了していない）か判定する
+func (r *Room) IsPlaying() bool {
+	return r.Game != nil && !r.Game.IsFinished
+}
+
+// NextDeadline は進行中のゲームを次に自動で進める期限を返す（なければゼロ値）
+// カード交換中は交換の期限と手番の期限の早い方
+func (r *Room) NextDeadline() time.Time {
+	if !r.IsPlaying() {
+		return time.Time{}
+	}
+	g := r.Game
+	deadline := g.TurnDeadline
+	if g.IsExchanging() && !g.ExchangeDeadline.IsZero() && (deadline.IsZero() || g.ExchangeDeadline.Before(deadline)) {
+		deadline = g.ExchangeDeadline
+	}
+	return deadline
+}
+
+// FinishedRecord は終了したゲームの未保存の記録を返す（なければ nil）
+func (r *Room) FinishedRecord() *GameRecord {
+	g := r.Game
+	if g == nil || !g.IsFinished || g.Record == nil || g.RecordID != 0 {
+		return nil
+	}
+	return &GameRecord{
+		RoomID:     r.ID,
+		Record:     g.Record,
+		FinishedAt: time.Now(),
+	}
 }
 
 func (r *Room) RestartGame() {
-	r.Game = r.Game.Reset()
+	r.Game = r.Game.Reset(r.Rules)
+	r.Game.SpectatorDelay = r.spectatorDelay()
 }
 
-func NewRoom(name string, ownerID int64) *Room {
+// spectatorDelay は開始するゲームで観戦者に全員の手札を見せる場合の遅延（見せないなら0）
+func (r *Room) spectatorDelay() int {
+	if !r.SpectatorFullView {
+		return 0
+	}
+	return max(r.SpectatorDelay, 1)
+}
+
+func NewRoom(name string, ownerID int64, rules game.RuleSet) *Room {
 	return &Room{
 		Name:      name,
 		OwnerID:   ownerID,
 		MemberIDs: []int64{ownerID},
 		PrevRanks: make(map[int64]int),
+		Rules:     rules,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+
+		SpectatorDelay: DefaultSpectatorDelay,
 	}
 }
@@ -4,4 +4,6 @@ import "errors"
 
 var (
 	ErrEntityNotFound = errors.New("entity not found")
+	// ErrConflict は、読み込んだ後に他の更新が保存されていた
+	ErrConflict = errors.New("conflict")
 )
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ne241099/daifugo-server/model"
+)
+
+type GameRecordRepository interface {
+	// SaveGameRecord は、ゲームの記録を保存する
+	SaveGameRecord(ctx context.Context, record *model.GameRecord) error
+	// SaveGameRecordOnce は、終了したゲームの記録を保存する
+	// 同じ部屋・同じゲーム（SeedHash）の記録が既にあれば保存せず、そのIDを設定する
+	SaveGameRecordOnce(ctx context.Context, record *model.GameRecord) error
+	// GetGameRecordByID は、IDからゲームの記録を取得する
+	GetGameRecordByID(ctx context.Context, id int64) (*model.GameRecord, error)
+}
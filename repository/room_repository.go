@@ -2,19 +2,54 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/ne241099/daifugo-server/model"
 )
 
+// RoomRepository は部屋を保存する
+// 取得した部屋は呼び出し側ごとの複製で、変更は保存するまで他に見えない
 type RoomRepository interface {
 	// SaveRoom は、部屋を保存する
+	// 新規作成（ID が0）の場合は Version を1にする
+	// 更新の場合は保存されている Version が room.Version と一致するときだけ保存して Version を1つ増やし、
+	// 一致しなければ（読み込んだ後に他の更新があれば）ErrConflict を返す
 	SaveRoom(ctx context.Context, room *model.Room) error
 	// DeleteRoom は、部屋を削除する
 	DeleteRoom(ctx context.Context, id int64) error
-	// UpdateRoom は、部屋情報を更新する
+	// UpdateRoom は、部屋情報を更新する（SaveRoom と同じく競合すれば ErrConflict を返す）
 	UpdateRoom(ctx context.Context, room *model.Room) error
 	// ListRooms は、部屋一覧を取得する
 	ListRooms(ctx context.Context) ([]*model.Room, error)
+	// ListRoomsByUserID は、ユーザーがメンバーか観戦者になっている部屋を取得する
+	ListRoomsByUserID(ctx context.Context, userID int64) ([]*model.Room, error)
+	// ListDueRooms は、ゲームが進行中の部屋のうち、NextDeadline が now 以前か、
+	// userIDs のいずれかがメンバーになっている部屋を取得する（期限切れと切断の監視用）
+	ListDueRooms(ctx context.Context, now time.Time, userIDs []int64) ([]*model.Room, error)
 	// GetRoomByID は、IDから部屋を取得する
 	GetRoomByID(ctx context.Context, id int64) (*model.Room, error)
+	// CleanupRooms は、expiration の間更新されていない部屋を削除する
+	CleanupRooms(expiration time.Duration)
+}
+
+// MaxConflictRetries は、部屋の更新が競合した場合にやり直す回数
+const MaxConflictRetries = 5
+
+// RetryOnConflict は、fn が ErrConflict を返した場合に部屋の読み込みからやり直す
+// fn は毎回 GetRoomByID で最新の部屋を取得してから変更・保存すること
+// やり直しても競合する場合は ErrConflict を返す
+func RetryOnConflict[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var result T
+	var err error
+	for range MaxConflictRetries {
+		result, err = fn()
+		if !errors.Is(err, ErrConflict) {
+			return result, err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return result, ctxErr
+		}
+	}
+	return result, err
 }
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+// SaveFinishedRecord はゲームが終了していれば記録を保存し、そのIDを room に設定する
+// 同じゲームの記録が既にあればそれを使うため、やり直しても記録は1つにしかならない
+func SaveFinishedRecord(ctx context.Context, repo repository.GameRecordRepository, room *model.Room) error {
+	record := room.FinishedRecord()
+	if repo == nil || record == nil {
+		return nil
+	}
+	if err := repo.SaveGameRecordOnce(ctx, record); err != nil {
+		return fmt.Errorf("failed to save game record: %w", err)
+	}
+	room.Game.RecordID = record.ID
+	return nil
+}
+
+// ArchiveGame は保存済みの部屋のゲームが終了していれば記録を保存し、そのIDを部屋に反映する
+// 保存に失敗した操作の記録が残らないよう、部屋の保存が成功した後に呼ぶ
+func ArchiveGame(ctx context.Context, records repository.GameRecordRepository, rooms repository.RoomRepository, room *model.Room) (*model.Room, error) {
+	if room == nil || room.FinishedRecord() == nil || records == nil {
+		return room, nil
+	}
+	seedHash := room.Game.SeedHash
+
+	return repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		current, err := rooms.GetRoomByID(ctx, room.ID)
+		if errors.Is(err, repository.ErrEntityNotFound) {
+			// 部屋が削除されていても記録だけは残す
+			return room, SaveFinishedRecord(ctx, records, room)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("room not found: %w", err)
+		}
+
+		// その間に再開された場合や、既に記録済みの場合は何もしない
+		if current.Game == nil || current.Game.SeedHash != seedHash || current.FinishedRecord() == nil {
+			return current, nil
+		}
+
+		if err := SaveFinishedRecord(ctx, records, current); err != nil {
+			return nil, err
+		}
+		if err := rooms.SaveRoom(ctx, current); err != nil {
+			return nil, err
+		}
+		return current, nil
+	})
+}
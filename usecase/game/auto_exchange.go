@@ -0,0 +1,58 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type AutoExchangeUseCase interface {
+	Execute(ctx context.Context, roomID int64) (*model.Room, bool, error)
+}
+
+var _ AutoExchangeUseCase = &AutoExchangeInteractor{}
+
+// AutoExchangeInteractor は期限を過ぎたカード交換を自動で完了させる
+type AutoExchangeInteractor struct {
+	RoomRepository repository.RoomRepository
+}
+
+// Execute は交換を自動で完了させた場合に true を返す
+func (uc *AutoExchangeInteractor) Execute(ctx context.Context, roomID int64) (*model.Room, bool, error) {
+	var exchanged bool
+	room, err := repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		room, ok, err := uc.attempt(ctx, roomID)
+		exchanged = ok
+		return room, err
+	})
+	return room, exchanged, err
+}
+
+func (uc *AutoExchangeInteractor) attempt(ctx context.Context, roomID int64) (*model.Room, bool, error) {
+	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, false, fmt.Errorf("room not found: %w", err)
+	}
+
+	g := room.Game
+	if g == nil || !g.IsExchanging() {
+		return room, false, nil
+	}
+
+	// 期限前（再スタート後の別の交換など）なら何もしない
+	if g.ExchangeDeadline.IsZero() || time.Now().Before(g.ExchangeDeadline) {
+		return room, false, nil
+	}
+
+	g.AutoExchange()
+
+	room.RefreshTurnDeadline()
+	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
+		return nil, false, err
+	}
+
+	return room, true, nil
+}
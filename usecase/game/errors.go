@@ -0,0 +1,26 @@
+package game
+
+import "errors"
+
+var (
+	// ErrInvalidMove は、ルール上出せないカードを指定した（部屋の状態は変わっていない）
+	ErrInvalidMove = errors.New("invalid move")
+)
+
+// invalidMoveError は元のメッセージのまま、ErrInvalidMove として判定できるようにする
+type invalidMoveError struct {
+	err error
+}
+
+func (e *invalidMoveError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidMoveError) Unwrap() []error {
+	return []error{ErrInvalidMove, e.err}
+}
+
+// invalidMove は err を ErrInvalidMove として判定できるエラーにする
+func invalidMove(err error) error {
+	return &invalidMoveError{err: err}
+}
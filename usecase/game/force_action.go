@@ -0,0 +1,56 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+	"github.com/ne241099/daifugo-server/usecase"
+)
+
+type ForceActionUseCase interface {
+	Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, bool, error)
+}
+
+var _ ForceActionUseCase = &ForceActionInteractor{}
+
+// ForceActionInteractor は切断したプレイヤーの入力待ちを代わりに進める
+type ForceActionInteractor struct {
+	RoomRepository       repository.RoomRepository
+	GameRecordRepository repository.GameRecordRepository
+}
+
+// Execute は代わりに行動したかを返す（そのプレイヤーの入力待ちでなければ false）
+func (uc *ForceActionInteractor) Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, bool, error) {
+	var acted bool
+	room, err := repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		room, ok, err := uc.attempt(ctx, roomID, userID)
+		acted = ok
+		return room, err
+	})
+	if err != nil {
+		return room, acted, err
+	}
+	// 部屋の保存が確定してから記録を残す
+	room, err = usecase.ArchiveGame(ctx, uc.GameRecordRepository, uc.RoomRepository, room)
+	return room, acted, err
+}
+
+func (uc *ForceActionInteractor) attempt(ctx context.Context, roomID int64, userID int64) (*model.Room, bool, error) {
+	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, false, fmt.Errorf("room not found: %w", err)
+	}
+
+	if room.Game == nil || !room.Game.ForceAction(userID) {
+		return room, false, nil
+	}
+
+	room.RefreshTurnDeadline()
+	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
+		return nil, false, err
+	}
+
+	return room, true, nil
+}
@@ -0,0 +1,27 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type GetGameRecordUseCase interface {
+	Execute(ctx context.Context, recordID int64) (*model.GameRecord, error)
+}
+
+var _ GetGameRecordUseCase = &GetGameRecordInteractor{}
+
+type GetGameRecordInteractor struct {
+	GameRecordRepository repository.GameRecordRepository
+}
+
+func (uc *GetGameRecordInteractor) Execute(ctx context.Context, recordID int64) (*model.GameRecord, error) {
+	record, err := uc.GameRecordRepository.GetGameRecordByID(ctx, recordID)
+	if err != nil {
+		return nil, fmt.Errorf("game record not found: %w", err)
+	}
+	return record, nil
+}
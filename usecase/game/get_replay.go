@@ -0,0 +1,40 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type GetReplayUseCase interface {
+	Execute(ctx context.Context, recordID int64, step *int) (*model.GameRecord, *game.Game, int, error)
+}
+
+var _ GetReplayUseCase = &GetReplayInteractor{}
+
+// GetReplayInteractor は保存した記録から任意の時点のゲームの状態を再現する
+type GetReplayInteractor struct {
+	GameRecordRepository repository.GameRecordRepository
+}
+
+// Execute は step 手目の状態と実際の手数を返す（step を省略した場合は最終状態）
+func (uc *GetReplayInteractor) Execute(ctx context.Context, recordID int64, step *int) (*model.GameRecord, *game.Game, int, error) {
+	record, err := uc.GameRecordRepository.GetGameRecordByID(ctx, recordID)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("game record not found: %w", err)
+	}
+
+	s := len(record.Record.Actions)
+	if step != nil {
+		s = *step
+	}
+
+	g, err := game.Replay(record.Record, s)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return record, g, s, nil
+}
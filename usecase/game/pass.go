@@ -6,6 +6,7 @@ import (
 
 	"github.com/ne241099/daifugo-server/model"
 	"github.com/ne241099/daifugo-server/repository"
+	"github.com/ne241099/daifugo-server/usecase"
 )
 
 type PassUseCase interface {
@@ -15,16 +16,26 @@ type PassUseCase interface {
 var _ PassUseCase = &PassInteractor{}
 
 type PassInteractor struct {
-	RoomRepository repository.RoomRepository
+	RoomRepository       repository.RoomRepository
+	GameRecordRepository repository.GameRecordRepository
 }
 
 func (uc *PassInteractor) Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
+	room, err := repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 部屋の保存が確定してから記録を残す
+	return usecase.ArchiveGame(ctx, uc.GameRecordRepository, uc.RoomRepository, room)
+}
+
+func (uc *PassInteractor) attempt(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
 	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
 	if err != nil {
 		return nil, fmt.Errorf("room not found: %w", err)
 	}
-	room.Mu.Lock()
-	defer room.Mu.Unlock()
 
 	if room.Game == nil {
 		return nil, fmt.Errorf("game not started")
@@ -34,6 +45,7 @@ func (uc *PassInteractor) Execute(ctx context.Context, roomID int64, userID int6
 		return nil, err
 	}
 
+	room.RefreshTurnDeadline()
 	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
 		return nil, err
 	}
@@ -7,6 +7,7 @@ import (
 	"github.com/ne241099/daifugo-server/internal/game"
 	"github.com/ne241099/daifugo-server/model"
 	"github.com/ne241099/daifugo-server/repository"
+	"github.com/ne241099/daifugo-server/usecase"
 )
 
 type PlayCardUseCase interface {
@@ -16,16 +17,26 @@ type PlayCardUseCase interface {
 var _ PlayCardUseCase = &PlayCardInteractor{}
 
 type PlayCardInteractor struct {
-	RoomRepository repository.RoomRepository
+	RoomRepository       repository.RoomRepository
+	GameRecordRepository repository.GameRecordRepository
 }
 
 func (uc *PlayCardInteractor) Execute(ctx context.Context, roomID int64, userID int64, cardIDs []int) (*model.Room, error) {
+	room, err := repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID, userID, cardIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 部屋の保存が確定してから記録を残す
+	return usecase.ArchiveGame(ctx, uc.GameRecordRepository, uc.RoomRepository, room)
+}
+
+func (uc *PlayCardInteractor) attempt(ctx context.Context, roomID int64, userID int64, cardIDs []int) (*model.Room, error) {
 	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
 	if err != nil {
 		return nil, fmt.Errorf("room not found: %w", err)
 	}
-	room.Mu.Lock()
-	defer room.Mu.Unlock()
 
 	if room.Game == nil {
 		return nil, fmt.Errorf("game not started")
@@ -55,14 +66,15 @@ func (uc *PlayCardInteractor) Execute(ctx context.Context, roomID int64, userID
 			}
 		}
 		if !found {
-			return nil, fmt.Errorf("card %d not found in player's hand", cid)
+			return nil, invalidMove(fmt.Errorf("card %d not found in player's hand", cid))
 		}
 	}
 
 	// ロジック実行
 	if err := room.Game.Play(userID, targetCards); err != nil {
-		return nil, err
+		return nil, invalidMove(err)
 	}
+	room.RefreshTurnDeadline()
 	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
 		return nil, err
 	}
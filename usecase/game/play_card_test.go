@@ -0,0 +1,137 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ne241099/daifugo-server/infra/inmem"
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+	"github.com/ne241099/daifugo-server/usecase"
+)
+
+// conflictRoomRepository は SaveRoom を常に競合させる
+type conflictRoomRepository struct {
+	repository.RoomRepository
+}
+
+func (r *conflictRoomRepository) SaveRoom(ctx context.Context, room *model.Room) error {
+	return repository.ErrConflict
+}
+
+// newLastMoveRoom は次の1手（手番のプレイヤーが手札をすべて出す）でゲームが終わる部屋を保存する
+func newLastMoveRoom(t *testing.T, rooms repository.RoomRepository) (*model.Room, int64, []int) {
+	t.Helper()
+	g := game.NewGameWithSeed([]int64{1, 2}, game.RuleSet{}, game.Seed{1})
+
+	for step := 0; ; step++ {
+		if step > 1000 || g.IsFinished {
+			t.Fatal("最後の1手の局面にならない")
+		}
+		p := g.Players[g.Turn]
+		moves := g.LegalMoves(p.UserID)
+		for _, m := range moves {
+			if len(m) == len(p.Hand) {
+				room := &model.Room{Name: "test", OwnerID: 1, MemberIDs: []int64{1, 2}, Game: g}
+				if err := rooms.SaveRoom(context.Background(), room); err != nil {
+					t.Fatal(err)
+				}
+				ids := make([]int, len(m))
+				for i, c := range m {
+					ids[i] = c.ID
+				}
+				return room, p.UserID, ids
+			}
+		}
+
+		var err error
+		if len(moves) > 0 {
+			err = g.Play(p.UserID, moves[0])
+		} else {
+			err = g.Pass(p.UserID)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPlayCardArchivesAfterSave(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("部屋の保存が競合したら記録しない", func(t *testing.T) {
+		rooms := inmem.NewInmemRoomRepository()
+		records := inmem.NewInmemGameRecordRepository()
+		room, userID, cardIDs := newLastMoveRoom(t, rooms)
+
+		uc := &PlayCardInteractor{RoomRepository: &conflictRoomRepository{rooms}, GameRecordRepository: records}
+		if _, err := uc.Execute(ctx, room.ID, userID, cardIDs); !errors.Is(err, repository.ErrConflict) {
+			t.Fatalf("err = %v, want ErrConflict", err)
+		}
+		if _, err := records.GetGameRecordByID(ctx, 1); !errors.Is(err, repository.ErrEntityNotFound) {
+			t.Fatalf("確定していない手の記録が残っている: %v", err)
+		}
+	})
+
+	t.Run("保存に成功したら1つだけ記録する", func(t *testing.T) {
+		rooms := inmem.NewInmemRoomRepository()
+		records := inmem.NewInmemGameRecordRepository()
+		room, userID, cardIDs := newLastMoveRoom(t, rooms)
+
+		uc := &PlayCardInteractor{RoomRepository: rooms, GameRecordRepository: records}
+		got, err := uc.Execute(ctx, room.ID, userID, cardIDs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Game.IsFinished || got.Game.RecordID == 0 {
+			t.Fatalf("終了したゲームの記録のIDが設定されていない: %d", got.Game.RecordID)
+		}
+
+		stored, err := rooms.GetRoomByID(ctx, room.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Game.RecordID != got.Game.RecordID {
+			t.Fatalf("保存された記録のID = %d, want %d", stored.Game.RecordID, got.Game.RecordID)
+		}
+
+		// 記録のIDを反映する前の部屋からやり直しても、同じ記録を使う
+		stored.Game.RecordID = 0
+		if err := usecase.SaveFinishedRecord(ctx, records, stored); err != nil {
+			t.Fatal(err)
+		}
+		if stored.Game.RecordID != got.Game.RecordID {
+			t.Fatalf("同じゲームの記録が重複した: %d, want %d", stored.Game.RecordID, got.Game.RecordID)
+		}
+		if _, err := records.GetGameRecordByID(ctx, got.Game.RecordID+1); !errors.Is(err, repository.ErrEntityNotFound) {
+			t.Fatalf("記録が2つ以上ある: %v", err)
+		}
+	})
+}
+
+func TestPlayCardInvalidMove(t *testing.T) {
+	ctx := context.Background()
+	rooms := inmem.NewInmemRoomRepository()
+	room, userID, _ := newLastMoveRoom(t, rooms)
+	uc := &PlayCardInteractor{RoomRepository: rooms}
+
+	// 持っていないカードも、手番でないプレイヤーもルール違反として判定できる
+	other := int64(3) - userID
+	for _, tc := range []struct {
+		userID  int64
+		cardIDs []int
+	}{
+		{userID, []int{999}},
+		{other, []int{room.Game.Players[other-1].Hand[0].ID}},
+	} {
+		_, err := uc.Execute(ctx, room.ID, tc.userID, tc.cardIDs)
+		if !errors.Is(err, ErrInvalidMove) {
+			t.Fatalf("user %d, cards %v: err = %v, want ErrInvalidMove", tc.userID, tc.cardIDs, err)
+		}
+		if err.Error() == ErrInvalidMove.Error() {
+			t.Fatalf("元のメッセージが失われている: %v", err)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ne241099/daifugo-server/model"
 	"github.com/ne241099/daifugo-server/repository"
+	"github.com/ne241099/daifugo-server/usecase"
 )
 
 type RestartGameUseCase interface {
@@ -15,21 +16,31 @@ type RestartGameUseCase interface {
 var _ RestartGameUseCase = &RestartGameInteractor{}
 
 type RestartGameInteractor struct {
-	RoomRepository repository.RoomRepository
+	RoomRepository       repository.RoomRepository
+	GameRecordRepository repository.GameRecordRepository
 }
 
 func (uc *RestartGameInteractor) Execute(ctx context.Context, roomID int64) (*model.Room, error) {
+	return repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID)
+	})
+}
+
+func (uc *RestartGameInteractor) attempt(ctx context.Context, roomID int64) (*model.Room, error) {
 	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
 	if err != nil {
 		return nil, err
 	}
-	room.Mu.Lock()
-	defer room.Mu.Unlock()
 
 	if room.Game == nil {
 		return nil, fmt.Errorf("game is not started")
 	}
 
+	// 破棄する前に記録を残す（同じゲームの記録は1つにしかならないため、競合してやり直しても重複しない）
+	if err := usecase.SaveFinishedRecord(ctx, uc.GameRecordRepository, room); err != nil {
+		return nil, err
+	}
+
 	if room.PrevRanks == nil {
 		room.PrevRanks = make(map[int64]int)
 	}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type SetAutoPlayUseCase interface {
+	Execute(ctx context.Context, roomID int64, userID int64, enabled bool) (*model.Room, bool, error)
+}
+
+var _ SetAutoPlayUseCase = &SetAutoPlayInteractor{}
+
+// SetAutoPlayInteractor は人間の席のボットによる代打ちを切り替える
+type SetAutoPlayInteractor struct {
+	RoomRepository repository.RoomRepository
+}
+
+// Execute は代打ちの状態が変わったかを返す（ゲームに参加していない・終了後なら false）
+func (uc *SetAutoPlayInteractor) Execute(ctx context.Context, roomID int64, userID int64, enabled bool) (*model.Room, bool, error) {
+	var changed bool
+	room, err := repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		room, ok, err := uc.attempt(ctx, roomID, userID, enabled)
+		changed = ok
+		return room, err
+	})
+	return room, changed, err
+}
+
+func (uc *SetAutoPlayInteractor) attempt(ctx context.Context, roomID int64, userID int64, enabled bool) (*model.Room, bool, error) {
+	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, false, fmt.Errorf("room not found: %w", err)
+	}
+
+	g := room.Game
+	if g == nil || g.IsFinished || g.IsBotControlled(userID) == enabled {
+		return room, false, nil
+	}
+
+	if err := g.SetAutoPlay(userID, enabled); err != nil {
+		return room, false, nil
+	}
+
+	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
+		return nil, false, err
+	}
+
+	return room, true, nil
+}
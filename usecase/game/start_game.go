@@ -3,6 +3,7 @@ package game
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ne241099/daifugo-server/model"
 	"github.com/ne241099/daifugo-server/repository"
@@ -16,21 +17,27 @@ var _ StartGameUseCase = &StartGameInteractor{}
 
 type StartGameInteractor struct {
 	RoomRepository repository.RoomRepository
+	// ExchangeTimeout カード交換の制限時間（0なら無制限）
+	ExchangeTimeout time.Duration
 }
 
 func (uc *StartGameInteractor) Execute(ctx context.Context, roomID int64) (*model.Room, error) {
+	return repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID)
+	})
+}
+
+func (uc *StartGameInteractor) attempt(ctx context.Context, roomID int64) (*model.Room, error) {
 	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
 	if err != nil {
 		return nil, fmt.Errorf("room not found: %w", err)
 	}
-	room.Mu.Lock()
-	defer room.Mu.Unlock()
 
 	if room.Game != nil {
 		return nil, fmt.Errorf("game already started")
 	}
 
-	if len(room.MemberIDs) < 2 {
+	if len(room.SeatIDs()) < 2 {
 		return nil, fmt.Errorf("at least 2 players are required")
 	}
 
@@ -48,9 +55,14 @@ func (uc *StartGameInteractor) Execute(ctx context.Context, roomID int64) (*mode
 
 		// 順位がついている人がいれば、Reset() を呼んで手札交換を実行させる
 		if restoredCount > 0 {
-			room.Game = room.Game.Reset()
+			room.RestartGame()
 		}
 	}
+
+	if room.Game.IsExchanging() && uc.ExchangeTimeout > 0 {
+		room.Game.ExchangeDeadline = time.Now().Add(uc.ExchangeTimeout)
+	}
+	room.RefreshTurnDeadline()
 	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
 		return nil, err
 	}
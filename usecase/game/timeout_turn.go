@@ -0,0 +1,64 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+	"github.com/ne241099/daifugo-server/usecase"
+)
+
+type TimeoutTurnUseCase interface {
+	Execute(ctx context.Context, roomID int64) (*model.Room, []int64, error)
+}
+
+var _ TimeoutTurnUseCase = &TimeoutTurnInteractor{}
+
+// TimeoutTurnInteractor は制限時間を過ぎた手番を自動で進める
+type TimeoutTurnInteractor struct {
+	RoomRepository       repository.RoomRepository
+	GameRecordRepository repository.GameRecordRepository
+}
+
+// Execute は代わりに行動したプレイヤーのIDを返す（期限前なら空）
+func (uc *TimeoutTurnInteractor) Execute(ctx context.Context, roomID int64) (*model.Room, []int64, error) {
+	var userIDs []int64
+	room, err := repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		room, ids, err := uc.attempt(ctx, roomID)
+		userIDs = ids
+		return room, err
+	})
+	if err != nil {
+		return room, userIDs, err
+	}
+	// 部屋の保存が確定してから記録を残す
+	room, err = usecase.ArchiveGame(ctx, uc.GameRecordRepository, uc.RoomRepository, room)
+	return room, userIDs, err
+}
+
+func (uc *TimeoutTurnInteractor) attempt(ctx context.Context, roomID int64) (*model.Room, []int64, error) {
+	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("room not found: %w", err)
+	}
+
+	g := room.Game
+	if g == nil || g.IsFinished {
+		return room, nil, nil
+	}
+
+	// 期限前（その後に誰かが行動した場合など）なら何もしない
+	if g.TurnDeadline.IsZero() || time.Now().Before(g.TurnDeadline) {
+		return room, nil, nil
+	}
+
+	userIDs := g.ForceTimeout()
+	room.RefreshTurnDeadline()
+	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
+		return nil, nil, err
+	}
+
+	return room, userIDs, nil
+}
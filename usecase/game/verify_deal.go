@@ -0,0 +1,48 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/internal/game"
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+// DealVerification は種から手札を配り直した結果
+type DealVerification struct {
+	Record *model.GameRecord
+	// 種から配り直した手札（席順）
+	Hands [][]*game.Card
+	// 一致しなかった理由（一致すれば nil）
+	Mismatch error
+}
+
+type VerifyDealUseCase interface {
+	Execute(ctx context.Context, recordID int64) (*DealVerification, error)
+}
+
+var _ VerifyDealUseCase = &VerifyDealInteractor{}
+
+// VerifyDealInteractor は終了したゲームの公開された種から手札を配り直して検証する
+type VerifyDealInteractor struct {
+	GameRecordRepository repository.GameRecordRepository
+}
+
+func (uc *VerifyDealInteractor) Execute(ctx context.Context, recordID int64) (*DealVerification, error) {
+	record, err := uc.GameRecordRepository.GetGameRecordByID(ctx, recordID)
+	if err != nil {
+		return nil, fmt.Errorf("game record not found: %w", err)
+	}
+
+	seed, err := game.ParseSeed(record.Record.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("record has no seed: %w", err)
+	}
+
+	return &DealVerification{
+		Record:   record,
+		Hands:    game.DealFromSeed(seed, record.Record.Rules.JokerCount, len(record.Record.Seats)),
+		Mismatch: game.VerifyDeal(record.Record),
+	}, nil
+}
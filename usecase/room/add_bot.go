@@ -0,0 +1,51 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type AddBotUseCase interface {
+	Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, error)
+}
+
+var _ AddBotUseCase = &AddBotInteractor{}
+
+type AddBotInteractor struct {
+	RoomRepository repository.RoomRepository
+}
+
+func (uc *AddBotInteractor) Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
+	return repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID, userID)
+	})
+}
+
+func (uc *AddBotInteractor) attempt(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
+	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("room not found: %w", err)
+	}
+
+	if room.OwnerID != userID {
+		return nil, errors.New("only the owner can add bots")
+	}
+	if room.Game != nil {
+		return nil, errors.New("game already started")
+	}
+	if room.IsFull() {
+		return nil, errors.New("room is full")
+	}
+
+	room.AddBot()
+
+	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
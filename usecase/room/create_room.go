@@ -3,12 +3,13 @@ package room
 import (
 	"context"
 
+	"github.com/ne241099/daifugo-server/internal/game"
 	"github.com/ne241099/daifugo-server/model"
 	"github.com/ne241099/daifugo-server/repository"
 )
 
 type CreateRoomUseCase interface {
-	Execute(ctx context.Context, name string, ownerID int64) (*model.Room, error)
+	Execute(ctx context.Context, name string, ownerID int64, rules game.RuleSet) (*model.Room, error)
 }
 
 var _ CreateRoomUseCase = &CreateRoomInteractor{}
@@ -17,8 +18,12 @@ type CreateRoomInteractor struct {
 	RoomRepository repository.RoomRepository
 }
 
-func (uc *CreateRoomInteractor) Execute(ctx context.Context, name string, ownerID int64) (*model.Room, error) {
-	room := model.NewRoom(name, ownerID)
+func (uc *CreateRoomInteractor) Execute(ctx context.Context, name string, ownerID int64, rules game.RuleSet) (*model.Room, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	room := model.NewRoom(name, ownerID, rules)
 
 	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
 		return nil, err
@@ -19,12 +19,16 @@ type JoinRoomInteractor struct {
 }
 
 func (uc *JoinRoomInteractor) Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
+	return repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID, userID)
+	})
+}
+
+func (uc *JoinRoomInteractor) attempt(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
 	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
 	if err != nil {
 		return nil, err
 	}
-	room.Mu.Lock()
-	defer room.Mu.Unlock()
 
 	if room.IsFull() {
 		return nil, errors.New("room is full")
@@ -37,6 +41,8 @@ func (uc *JoinRoomInteractor) Execute(ctx context.Context, roomID int64, userID
 	}
 
 	room.MemberIDs = append(room.MemberIDs, userID)
+	// 観戦していた場合はプレイヤーとして参加する
+	room.RemoveSpectator(userID)
 
 	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
 		return nil, err
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ne241099/daifugo-server/model"
 	"github.com/ne241099/daifugo-server/repository"
+	"github.com/ne241099/daifugo-server/usecase"
 )
 
 type LeaveRoomUseCase interface {
@@ -14,17 +16,28 @@ type LeaveRoomUseCase interface {
 var _ LeaveRoomUseCase = &LeaveRoomInteractor{}
 
 type LeaveRoomInteractor struct {
-	RoomRepository repository.RoomRepository
+	RoomRepository       repository.RoomRepository
+	GameRecordRepository repository.GameRecordRepository
 }
 
 func (uc *LeaveRoomInteractor) Execute(ctx context.Context, roomID int64, userID int64) error {
+	room, err := repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID, userID)
+	})
+	if err != nil {
+		return err
+	}
+	// 退出でゲームが終了した場合は、部屋の保存が確定してから記録を残す
+	_, err = usecase.ArchiveGame(ctx, uc.GameRecordRepository, uc.RoomRepository, room)
+	return err
+}
+
+func (uc *LeaveRoomInteractor) attempt(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
 	// 部屋情報を取得
 	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
 	if err != nil {
-		return fmt.Errorf("room not found: %w", err)
+		return nil, fmt.Errorf("room not found: %w", err)
 	}
-	room.Mu.Lock()
-	defer room.Mu.Unlock()
 
 	// メンバーリストからユーザーを削除
 	newMembers := make([]int64, 0, len(room.MemberIDs))
@@ -39,20 +52,13 @@ func (uc *LeaveRoomInteractor) Execute(ctx context.Context, roomID int64, userID
 	}
 
 	if !found {
-		return fmt.Errorf("user is not in the room")
+		return nil, fmt.Errorf("user is not in the room")
 	}
 	room.MemberIDs = newMembers
 
 	if room.Game != nil {
 		room.Game.RemovePlayer(userID)
-	}
-
-	// 部屋が空になった場合は削除
-	if len(room.MemberIDs) == 0 {
-		if err := uc.RoomRepository.DeleteRoom(ctx, roomID); err != nil {
-			return fmt.Errorf("failed to delete empty room: %w", err)
-		}
-		return nil
+		room.RefreshTurnDeadline()
 	}
 
 	// オーナーが退出した場合は新しいオーナーを設定
@@ -60,10 +66,17 @@ func (uc *LeaveRoomInteractor) Execute(ctx context.Context, roomID int64, userID
 		room.OwnerID = room.MemberIDs[0]
 	}
 
-	// 更新を保存
+	// 更新を保存（空にする場合も、その間に参加した人がいないか確かめるため先に保存する）
 	if err := uc.RoomRepository.UpdateRoom(ctx, room); err != nil {
-		return fmt.Errorf("failed to update room: %w", err)
+		return nil, fmt.Errorf("failed to update room: %w", err)
+	}
+
+	// 部屋が空になった場合は削除
+	if len(room.MemberIDs) == 0 {
+		if err := uc.RoomRepository.DeleteRoom(ctx, roomID); err != nil {
+			return nil, fmt.Errorf("failed to delete empty room: %w", err)
+		}
 	}
 
-	return nil
+	return room, nil
 }
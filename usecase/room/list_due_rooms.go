@@ -0,0 +1,25 @@
+package room
+
+import (
+	"context"
+	"time"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type ListDueRoomsUseCase interface {
+	Execute(ctx context.Context, now time.Time, userIDs []int64) ([]*model.Room, error)
+}
+
+var _ ListDueRoomsUseCase = &ListDueRoomsInteractor{}
+
+// ListDueRoomsInteractor は自動で進める必要があるかもしれない進行中の部屋を取得する
+// 期限が来た部屋と、切断したプレイヤー（userIDs）がいる部屋だけを読み込む
+type ListDueRoomsInteractor struct {
+	RoomRepository repository.RoomRepository
+}
+
+func (uc *ListDueRoomsInteractor) Execute(ctx context.Context, now time.Time, userIDs []int64) ([]*model.Room, error) {
+	return uc.RoomRepository.ListDueRooms(ctx, now, userIDs)
+}
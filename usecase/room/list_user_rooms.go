@@ -0,0 +1,23 @@
+package room
+
+import (
+	"context"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type ListUserRoomsUseCase interface {
+	Execute(ctx context.Context, userID int64) ([]*model.Room, error)
+}
+
+var _ ListUserRoomsUseCase = &ListUserRoomsInteractor{}
+
+// ListUserRoomsInteractor はユーザーが参加・観戦している部屋を取得する
+type ListUserRoomsInteractor struct {
+	RoomRepository repository.RoomRepository
+}
+
+func (uc *ListUserRoomsInteractor) Execute(ctx context.Context, userID int64) ([]*model.Room, error) {
+	return uc.RoomRepository.ListRoomsByUserID(ctx, userID)
+}
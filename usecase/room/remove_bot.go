@@ -0,0 +1,50 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type RemoveBotUseCase interface {
+	Execute(ctx context.Context, roomID int64, userID int64, botID int64) (*model.Room, error)
+}
+
+var _ RemoveBotUseCase = &RemoveBotInteractor{}
+
+type RemoveBotInteractor struct {
+	RoomRepository repository.RoomRepository
+}
+
+func (uc *RemoveBotInteractor) Execute(ctx context.Context, roomID int64, userID int64, botID int64) (*model.Room, error) {
+	return repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID, userID, botID)
+	})
+}
+
+func (uc *RemoveBotInteractor) attempt(ctx context.Context, roomID int64, userID int64, botID int64) (*model.Room, error) {
+	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("room not found: %w", err)
+	}
+
+	if room.OwnerID != userID {
+		return nil, errors.New("only the owner can remove bots")
+	}
+	if room.Game != nil {
+		return nil, errors.New("game already started")
+	}
+
+	if !room.RemoveBot(botID) {
+		return nil, errors.New("bot is not in the room")
+	}
+
+	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
@@ -0,0 +1,42 @@
+package room
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type SpectateRoomUseCase interface {
+	Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, error)
+}
+
+var _ SpectateRoomUseCase = &SpectateRoomInteractor{}
+
+type SpectateRoomInteractor struct {
+	RoomRepository repository.RoomRepository
+}
+
+func (uc *SpectateRoomInteractor) Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
+	return repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID, userID)
+	})
+}
+
+func (uc *SpectateRoomInteractor) attempt(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
+	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("room not found: %w", err)
+	}
+
+	if err := room.AddSpectator(userID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
@@ -0,0 +1,43 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type StopSpectatingUseCase interface {
+	Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, error)
+}
+
+var _ StopSpectatingUseCase = &StopSpectatingInteractor{}
+
+type StopSpectatingInteractor struct {
+	RoomRepository repository.RoomRepository
+}
+
+func (uc *StopSpectatingInteractor) Execute(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
+	return repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID, userID)
+	})
+}
+
+func (uc *StopSpectatingInteractor) attempt(ctx context.Context, roomID int64, userID int64) (*model.Room, error) {
+	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("room not found: %w", err)
+	}
+
+	if !room.RemoveSpectator(userID) {
+		return nil, errors.New("user is not spectating the room")
+	}
+
+	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
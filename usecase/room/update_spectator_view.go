@@ -0,0 +1,53 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ne241099/daifugo-server/model"
+	"github.com/ne241099/daifugo-server/repository"
+)
+
+type UpdateSpectatorViewUseCase interface {
+	Execute(ctx context.Context, roomID int64, userID int64, fullView bool, delay *int) (*model.Room, error)
+}
+
+var _ UpdateSpectatorViewUseCase = &UpdateSpectatorViewInteractor{}
+
+// UpdateSpectatorViewInteractor は観戦者に遅れて全員の手札を見せるかを設定する
+// 進行中のゲームには反映せず、次に開始するゲームから適用する
+type UpdateSpectatorViewInteractor struct {
+	RoomRepository repository.RoomRepository
+}
+
+func (uc *UpdateSpectatorViewInteractor) Execute(ctx context.Context, roomID int64, userID int64, fullView bool, delay *int) (*model.Room, error) {
+	return repository.RetryOnConflict(ctx, func() (*model.Room, error) {
+		return uc.attempt(ctx, roomID, userID, fullView, delay)
+	})
+}
+
+func (uc *UpdateSpectatorViewInteractor) attempt(ctx context.Context, roomID int64, userID int64, fullView bool, delay *int) (*model.Room, error) {
+	room, err := uc.RoomRepository.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("room not found: %w", err)
+	}
+
+	if room.OwnerID != userID {
+		return nil, errors.New("only the owner can change the spectator view")
+	}
+	if delay != nil {
+		// 直前の手まで見えると観戦者経由で手札が漏れるため、1手以上は遅らせる
+		if *delay < 1 {
+			return nil, errors.New("delay must be at least 1")
+		}
+		room.SpectatorDelay = *delay
+	}
+	room.SpectatorFullView = fullView
+
+	if err := uc.RoomRepository.SaveRoom(ctx, room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}